@@ -7,19 +7,33 @@ type Agent struct {
 	Role            string
 	Goal            string
 	AllowDelegation bool
-	Model           string
+	Model           string // Esquema de URL (ex.: "groq://...", "ollama://host:porta/modelo") consultado por llm.ProviderForModel
 	Backstory       string
+
+	Temperature      float64            // Temperatura repassada ao Provider em HiveMind.Execute
+	MaxTokens        int                // Número máximo de tokens por resposta
+	ContextWindow    int                // Tamanho da janela de contexto repassado ao Provider
+	PerformanceStats map[string]float64 // Estatísticas de performance, incluindo "token_usage"
 }
 
 // Clone cria uma cópia do agente
 func (a *Agent) Clone() *Agent {
+	stats := make(map[string]float64, len(a.PerformanceStats))
+	for k, v := range a.PerformanceStats {
+		stats[k] = v
+	}
+
 	return &Agent{
-		ID:              a.ID,
-		Name:            a.Name,
-		Role:            a.Role,
-		Goal:            a.Goal,
-		AllowDelegation: a.AllowDelegation,
-		Model:           a.Model,
-		Backstory:       a.Backstory,
+		ID:               a.ID,
+		Name:             a.Name,
+		Role:             a.Role,
+		Goal:             a.Goal,
+		AllowDelegation:  a.AllowDelegation,
+		Model:            a.Model,
+		Backstory:        a.Backstory,
+		Temperature:      a.Temperature,
+		MaxTokens:        a.MaxTokens,
+		ContextWindow:    a.ContextWindow,
+		PerformanceStats: stats,
 	}
 }
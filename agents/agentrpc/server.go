@@ -0,0 +1,31 @@
+package agentrpc
+
+import (
+	"context"
+	"fmt"
+)
+
+// Server é a base que ObserverInfrastructureAgent e
+// OrchestratorInfrastructureAgent embutem para satisfazer AgentAPIServer:
+// cada um implementa apenas os métodos relevantes ao seu papel (Observer
+// cuida de PushMetrics/ReportHealth, Orchestrator de PullTasks/
+// RequestScale), deixando os demais cair nos stubs "não implementado"
+// abaixo por promoção de método — o mesmo padrão de CognitiveAgent
+// sobrescrevendo campos do embed que já existe no resto do módulo.
+type Server struct{}
+
+func (Server) PushMetrics(ctx context.Context, req *PushMetricsRequest) (*PushMetricsResponse, error) {
+	return nil, fmt.Errorf("agentrpc: PushMetrics não implementado por este servidor")
+}
+
+func (Server) ReportHealth(ctx context.Context, req *ReportHealthRequest) (*ReportHealthResponse, error) {
+	return nil, fmt.Errorf("agentrpc: ReportHealth não implementado por este servidor")
+}
+
+func (Server) PullTasks(ctx context.Context, req *PullTasksRequest) (*PullTasksResponse, error) {
+	return nil, fmt.Errorf("agentrpc: PullTasks não implementado por este servidor")
+}
+
+func (Server) RequestScale(ctx context.Context, req *RequestScaleRequest) (*RequestScaleResponse, error) {
+	return nil, fmt.Errorf("agentrpc: RequestScale não implementado por este servidor")
+}
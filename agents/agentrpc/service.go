@@ -0,0 +1,27 @@
+package agentrpc
+
+import "context"
+
+// AgentAPIServer é a interface que um handler de RPCs de agente implementa,
+// espelhando o serviço AgentAPI de proto/agent.proto.
+type AgentAPIServer interface {
+	PushMetrics(ctx context.Context, req *PushMetricsRequest) (*PushMetricsResponse, error)
+	ReportHealth(ctx context.Context, req *ReportHealthRequest) (*ReportHealthResponse, error)
+	PullTasks(ctx context.Context, req *PullTasksRequest) (*PullTasksResponse, error)
+	RequestScale(ctx context.Context, req *RequestScaleRequest) (*RequestScaleResponse, error)
+}
+
+// AgentAPIClient é a interface usada por CognitiveAgent para chamar o
+// AgentAPI exposto pela réplica do orquestrador dona deste agente.
+type AgentAPIClient interface {
+	PushMetrics(ctx context.Context, req *PushMetricsRequest) (*PushMetricsResponse, error)
+	ReportHealth(ctx context.Context, req *ReportHealthRequest) (*ReportHealthResponse, error)
+	PullTasks(ctx context.Context, req *PullTasksRequest) (*PullTasksResponse, error)
+	RequestScale(ctx context.Context, req *RequestScaleRequest) (*RequestScaleResponse, error)
+
+	// StreamLogs abre um stream de envio de LogLine, devolvendo uma função
+	// para enviar cada linha e uma para fechar o stream e obter a resposta
+	// final — o equivalente, nesta transport sobre Broker, ao stream de
+	// cliente que um drpc.Conn ofereceria nativamente.
+	StreamLogs(ctx context.Context) (send func(*LogLine) error, closeAndRecv func() (*StreamLogsResponse, error), err error)
+}
@@ -0,0 +1,328 @@
+package agentrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// exchangeKind e os prefixos de routing key usados pelo shim: requisições
+// vão em "rpc.request.<método>", respostas em "rpc.reply.<correlation_id>" —
+// o suficiente para simular chamada/resposta correlacionada sobre o broker
+// pub/sub existente. Não há transport DRPC real nem streaming bidirecional:
+// isto é JSON sobre o Broker (AMQP ou em memória), request/response apenas.
+const (
+	exchangeKind         = "topic"
+	requestRoutingPrefix = "rpc.request."
+	replyRoutingPrefix   = "rpc.reply."
+)
+
+// Transport é o subconjunto de agents.Broker que o shim precisa: qualquer
+// Broker (AMQP ou em memória) já o satisfaz estruturalmente, sem que este
+// pacote precise importar agents (o que criaria um ciclo, já que agents
+// embute agentrpc.Server).
+type Transport interface {
+	DeclareExchange(name, kind string) error
+	Publish(exchange, routingKey string, body []byte) error
+	Subscribe(exchange, bindingKey string) (<-chan []byte, error)
+}
+
+// envelope transporta uma chamada de RPC (ou sua resposta) como JSON sobre o
+// Broker — o equivalente, nesta transport, ao frame de uma mensagem DRPC.
+type envelope struct {
+	Method        string          `json:"method"`
+	CorrelationID string          `json:"correlation_id"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+	Err           string          `json:"err,omitempty"`
+}
+
+// Serve assina as requisições de RPC publicadas em exchange e as despacha
+// para os métodos de handler correspondentes, publicando a resposta de
+// volta em "rpc.reply.<correlation_id>". Bloqueia até ctx ser cancelado.
+func Serve(ctx context.Context, transport Transport, exchange string, handler AgentAPIServer) error {
+	if err := transport.DeclareExchange(exchange, exchangeKind); err != nil {
+		return fmt.Errorf("erro ao declarar exchange de agentrpc: %v", err)
+	}
+
+	msgs, err := transport.Subscribe(exchange, requestRoutingPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("erro ao assinar requisições de agentrpc: %v", err)
+	}
+
+	for {
+		select {
+		case body, ok := <-msgs:
+			if !ok {
+				return nil
+			}
+			go dispatch(ctx, transport, exchange, handler, body)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func dispatch(ctx context.Context, transport Transport, exchange string, handler AgentAPIServer, body []byte) {
+	var req envelope
+	if err := json.Unmarshal(body, &req); err != nil {
+		return
+	}
+
+	resp := envelope{CorrelationID: req.CorrelationID}
+
+	payload, err := callHandler(ctx, handler, req.Method, req.Payload)
+	if err != nil {
+		resp.Err = err.Error()
+	} else {
+		resp.Payload = payload
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+
+	_ = transport.Publish(exchange, replyRoutingPrefix+req.CorrelationID, data)
+}
+
+func callHandler(ctx context.Context, handler AgentAPIServer, method string, payload json.RawMessage) (json.RawMessage, error) {
+	switch method {
+	case "PushMetrics":
+		var req PushMetricsRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, err
+		}
+		resp, err := handler.PushMetrics(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resp)
+
+	case "ReportHealth":
+		var req ReportHealthRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, err
+		}
+		resp, err := handler.ReportHealth(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resp)
+
+	case "PullTasks":
+		var req PullTasksRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, err
+		}
+		resp, err := handler.PullTasks(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resp)
+
+	case "RequestScale":
+		var req RequestScaleRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, err
+		}
+		resp, err := handler.RequestScale(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resp)
+
+	default:
+		return nil, fmt.Errorf("agentrpc: método desconhecido %q", method)
+	}
+}
+
+// Client implementa AgentAPIClient sobre um Transport, correlacionando
+// requisição e resposta por um ID sequencial próprio por cliente.
+type Client struct {
+	transport Transport
+	exchange  string
+	agentID   string
+
+	nextID int64
+
+	mu      sync.Mutex
+	pending map[string]chan envelope
+}
+
+// NewClient cria um Client que publica requisições em exchange e assina suas
+// próprias respostas em "rpc.reply.<agentID>.*". Serve já deve estar
+// rodando do lado do servidor antes da primeira chamada.
+func NewClient(ctx context.Context, transport Transport, exchange, agentID string) (*Client, error) {
+	if err := transport.DeclareExchange(exchange, exchangeKind); err != nil {
+		return nil, fmt.Errorf("erro ao declarar exchange de agentrpc: %v", err)
+	}
+
+	replies, err := transport.Subscribe(exchange, replyRoutingPrefix+agentID+".*")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao assinar respostas de agentrpc: %v", err)
+	}
+
+	c := &Client{
+		transport: transport,
+		exchange:  exchange,
+		agentID:   agentID,
+		pending:   make(map[string]chan envelope),
+	}
+
+	go c.collectReplies(ctx, replies)
+
+	return c, nil
+}
+
+func (c *Client) collectReplies(ctx context.Context, replies <-chan []byte) {
+	for {
+		select {
+		case body, ok := <-replies:
+			if !ok {
+				return
+			}
+			var resp envelope
+			if err := json.Unmarshal(body, &resp); err != nil {
+				continue
+			}
+
+			c.mu.Lock()
+			ch, ok := c.pending[resp.CorrelationID]
+			if ok {
+				delete(c.pending, resp.CorrelationID)
+			}
+			c.mu.Unlock()
+
+			if ok {
+				ch <- resp
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Client) call(ctx context.Context, method string, req, resp interface{}) error {
+	correlationID := fmt.Sprintf("%s.%d", c.agentID, atomic.AddInt64(&c.nextID, 1))
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("erro ao criar JSON: %v", err)
+	}
+
+	ch := make(chan envelope, 1)
+	c.mu.Lock()
+	c.pending[correlationID] = ch
+	c.mu.Unlock()
+
+	data, err := json.Marshal(envelope{Method: method, CorrelationID: correlationID, Payload: payload})
+	if err != nil {
+		return fmt.Errorf("erro ao criar JSON: %v", err)
+	}
+
+	if err := c.transport.Publish(c.exchange, requestRoutingPrefix+method, data); err != nil {
+		c.mu.Lock()
+		delete(c.pending, correlationID)
+		c.mu.Unlock()
+		return fmt.Errorf("erro ao publicar requisição de agentrpc: %v", err)
+	}
+
+	select {
+	case envResp := <-ch:
+		if envResp.Err != "" {
+			return fmt.Errorf("agentrpc: %s", envResp.Err)
+		}
+		if resp == nil {
+			return nil
+		}
+		return json.Unmarshal(envResp.Payload, resp)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) PushMetrics(ctx context.Context, req *PushMetricsRequest) (*PushMetricsResponse, error) {
+	var resp PushMetricsResponse
+	if err := c.call(ctx, "PushMetrics", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) ReportHealth(ctx context.Context, req *ReportHealthRequest) (*ReportHealthResponse, error) {
+	var resp ReportHealthResponse
+	if err := c.call(ctx, "ReportHealth", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) PullTasks(ctx context.Context, req *PullTasksRequest) (*PullTasksResponse, error) {
+	var resp PullTasksResponse
+	if err := c.call(ctx, "PullTasks", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) RequestScale(ctx context.Context, req *RequestScaleRequest) (*RequestScaleResponse, error) {
+	var resp RequestScaleResponse
+	if err := c.call(ctx, "RequestScale", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// StreamLogs publica cada LogLine enviada via send diretamente no exchange
+// (sem esperar resposta individual, para não bloquear o produtor), e ao
+// fechar aguarda uma StreamLogsResponse final correlacionada.
+func (c *Client) StreamLogs(ctx context.Context) (send func(*LogLine) error, closeAndRecv func() (*StreamLogsResponse, error), err error) {
+	correlationID := fmt.Sprintf("%s.stream.%d", c.agentID, atomic.AddInt64(&c.nextID, 1))
+
+	send = func(line *LogLine) error {
+		payload, err := json.Marshal(line)
+		if err != nil {
+			return fmt.Errorf("erro ao criar JSON: %v", err)
+		}
+
+		data, err := json.Marshal(envelope{Method: "StreamLogs", CorrelationID: correlationID, Payload: payload})
+		if err != nil {
+			return fmt.Errorf("erro ao criar JSON: %v", err)
+		}
+
+		return c.transport.Publish(c.exchange, requestRoutingPrefix+"StreamLogs", data)
+	}
+
+	ch := make(chan envelope, 1)
+	c.mu.Lock()
+	c.pending[correlationID] = ch
+	c.mu.Unlock()
+
+	closeAndRecv = func() (*StreamLogsResponse, error) {
+		data, err := json.Marshal(envelope{Method: "StreamLogsClose", CorrelationID: correlationID})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao criar JSON: %v", err)
+		}
+		if err := c.transport.Publish(c.exchange, requestRoutingPrefix+"StreamLogsClose", data); err != nil {
+			return nil, fmt.Errorf("erro ao publicar fechamento de stream de agentrpc: %v", err)
+		}
+
+		select {
+		case envResp := <-ch:
+			if envResp.Err != "" {
+				return nil, fmt.Errorf("agentrpc: %s", envResp.Err)
+			}
+			var resp StreamLogsResponse
+			if err := json.Unmarshal(envResp.Payload, &resp); err != nil {
+				return nil, err
+			}
+			return &resp, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return send, closeAndRecv, nil
+}
@@ -0,0 +1,80 @@
+// Package agentrpc implementa a API tipada descrita em proto/agent.proto
+// entre CognitiveAgent e o par ObserverInfrastructureAgent/
+// OrchestratorInfrastructureAgent. Nenhum codegen de DRPC/protobuf está
+// integrado ao build: os tipos abaixo espelham as mensagens do .proto à mão,
+// e Serve/Client (em shim.go) os serializam como JSON sobre o Broker já existente, em
+// request/response, não sobre o wire format real do protobuf nem com
+// streaming.
+package agentrpc
+
+// MetricSample é uma amostra nomeada de métrica enviada por PushMetrics.
+type MetricSample struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// PushMetricsRequest carrega as métricas mais recentes de um agente.
+type PushMetricsRequest struct {
+	AgentID string         `json:"agent_id"`
+	Samples []MetricSample `json:"samples"`
+}
+
+// PushMetricsResponse é a resposta vazia de um PushMetrics bem-sucedido.
+type PushMetricsResponse struct{}
+
+// ReportHealthRequest carrega o estado de saúde atual de um agente.
+type ReportHealthRequest struct {
+	AgentID        string  `json:"agent_id"`
+	IsProcessing   bool    `json:"is_processing"`
+	ProcessingTime float64 `json:"processing_time"`
+	ProbeState     string  `json:"probe_state"`
+}
+
+// ReportHealthResponse é a resposta vazia de um ReportHealth bem-sucedido.
+type ReportHealthResponse struct{}
+
+// PullTasksRequest pede até MaxTasks tarefas atribuíveis a AgentID.
+type PullTasksRequest struct {
+	AgentID  string `json:"agent_id"`
+	MaxTasks int32  `json:"max_tasks"`
+}
+
+// Task é a representação tipada de uma tarefa sobre o fio, equivalente a
+// agents.Task mas restrita aos campos relevantes para o agente chamador.
+type Task struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Priority int32  `json:"priority"`
+	Data     []byte `json:"data"`
+}
+
+// PullTasksResponse devolve as tarefas atribuídas ao agente chamador.
+type PullTasksResponse struct {
+	Tasks []Task `json:"tasks"`
+}
+
+// LogLine é uma linha de log transmitida via StreamLogs.
+type LogLine struct {
+	AgentID  string `json:"agent_id"`
+	Level    string `json:"level"`
+	Message  string `json:"message"`
+	UnixTime int64  `json:"unix_time"`
+}
+
+// StreamLogsResponse fecha um StreamLogs, reportando quantas linhas foram
+// recebidas.
+type StreamLogsResponse struct {
+	LinesReceived int64 `json:"lines_received"`
+}
+
+// RequestScaleRequest pede uma reavaliação de escala fora do ciclo normal de
+// ScaleSystem.
+type RequestScaleRequest struct {
+	AgentID string `json:"agent_id"`
+	Reason  string `json:"reason"`
+}
+
+// RequestScaleResponse informa se o pedido resultou em scale-out.
+type RequestScaleResponse struct {
+	Scaled bool `json:"scaled"`
+}
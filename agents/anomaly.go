@@ -0,0 +1,405 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AnomalyCategory classifica o tipo de anomalia detectada em AgentHealth.
+type AnomalyCategory string
+
+const (
+	AnomalyHeartbeatMissed AnomalyCategory = "heartbeat_missed"
+	AnomalySuccessRateDrop AnomalyCategory = "success_rate_drop"
+	AnomalySlowProcessing  AnomalyCategory = "slow_processing"
+	AnomalyRepeatedError   AnomalyCategory = "repeated_error"
+)
+
+// HealthAnomaly registra uma anomalia detectada para um agente num
+// determinado instante de avaliação.
+type HealthAnomaly struct {
+	AgentName string          `json:"agent_name"`
+	Category  AnomalyCategory `json:"category"`
+	Detail    string          `json:"detail"`
+	At        time.Time       `json:"at"`
+
+	// WarnFlag soma 1 a cada rodada de Evaluate em que o agente teve ao menos
+	// uma anomalia. AssistFlag soma 1 quando, além disso, o agente acabou de
+	// entrar em quarentena — sinalizando que o caso passou de "observar" para
+	// "precisa de intervenção humana".
+	WarnFlag   int `json:"warn_flag"`
+	AssistFlag int `json:"assist_flag"`
+}
+
+// agentBaseline acumula, por agente, a baseline EWMA de ProcessingTime e o
+// estado necessário para repeated-error tracking e auto-quarentena.
+type agentBaseline struct {
+	mean  float64
+	varEV float64
+	ready bool
+
+	lastErrorCode      TaskErrorCode
+	repeatedErrorCount int
+
+	consecutiveAnomalies int
+	quarantined          bool
+	recoveringSince      time.Time
+
+	warnFlag   int
+	assistFlag int
+}
+
+// observe atualiza a média e a variância EWMA de ProcessingTime com um novo
+// ponto x, usando o fator de decaimento alpha (α≈0.2 por padrão).
+func (b *agentBaseline) observe(x, alpha float64) {
+	if !b.ready {
+		b.mean = x
+		b.varEV = 0
+		b.ready = true
+		return
+	}
+
+	delta := x - b.mean
+	b.mean += alpha * delta
+	b.varEV = (1 - alpha) * (b.varEV + alpha*delta*delta)
+}
+
+func (b *agentBaseline) stddev() float64 {
+	return math.Sqrt(b.varEV)
+}
+
+// AnomalyRule avalia um aspecto de AgentHealth e devolve as anomalias
+// encontradas (tipicamente 0 ou 1), além das quatro categorias padrão
+// embutidas em defaultAnomalyDetector — usada para estender o detector com
+// verificações específicas de um deployment sem reimplementar as padrão.
+type AnomalyRule interface {
+	Evaluate(agentName string, health *AgentHealth, cfg AnomalyConfig) []HealthAnomaly
+}
+
+// AnomalyRuleFunc adapta uma função simples à interface AnomalyRule, no
+// mesmo espírito de http.HandlerFunc.
+type AnomalyRuleFunc func(agentName string, health *AgentHealth, cfg AnomalyConfig) []HealthAnomaly
+
+// Evaluate implementa AnomalyRule.
+func (f AnomalyRuleFunc) Evaluate(agentName string, health *AgentHealth, cfg AnomalyConfig) []HealthAnomaly {
+	return f(agentName, health, cfg)
+}
+
+// AnomalySink recebe cada HealthAnomaly assim que é detectada — a ponte para
+// sistemas externos de paging/observabilidade, plugada via
+// AnomalyDetector.AddSink. NewWebhookAnomalySink constrói o sink mais comum.
+type AnomalySink func(HealthAnomaly)
+
+// AnomalyConfig reúne os limiares usados pelas quatro checagens padrão de
+// defaultAnomalyDetector.
+type AnomalyConfig struct {
+	// HeartbeatStaleAfter marca AnomalyHeartbeatMissed quando LastHeartbeat é
+	// mais antigo que isso.
+	HeartbeatStaleAfter time.Duration
+
+	// SuccessRateFloor marca AnomalySuccessRateDrop quando SuccessRate cai
+	// abaixo disso.
+	SuccessRateFloor float64
+
+	// ProcessingBaselineAlpha é o α da EWMA de ProcessingTime.
+	ProcessingBaselineAlpha float64
+
+	// ProcessingStddevK é o k de "marcar AnomalySlowProcessing quando
+	// current > baseline + k·stddev".
+	ProcessingStddevK float64
+
+	// RepeatedErrorThreshold é quantas vezes seguidas o mesmo Error.Code deve
+	// se repetir para marcar AnomalyRepeatedError.
+	RepeatedErrorThreshold int
+
+	// QuarantineAfter é quantas anomalias consecutivas (qualquer categoria)
+	// colocam o agente em quarentena (IsQuarantined passa a true).
+	QuarantineAfter int
+
+	// RecoveryCooldown é por quanto tempo, após heartbeat e SuccessRate
+	// voltarem a ficar dentro dos limiares, o agente deve se manter assim
+	// antes de sair da quarentena.
+	RecoveryCooldown time.Duration
+}
+
+// DefaultAnomalyConfig retorna limiares razoáveis para produção, usados por
+// NewAnomalyDetector quando chamado diretamente com essa configuração.
+func DefaultAnomalyConfig() AnomalyConfig {
+	return AnomalyConfig{
+		HeartbeatStaleAfter:     30 * time.Second,
+		SuccessRateFloor:        0.5,
+		ProcessingBaselineAlpha: 0.2,
+		ProcessingStddevK:       3,
+		RepeatedErrorThreshold:  3,
+		QuarantineAfter:         5,
+		RecoveryCooldown:        2 * time.Minute,
+	}
+}
+
+// AnomalyDetector avalia periodicamente um snapshot de AgentHealth e emite
+// HealthAnomaly para seus sinks, mantendo o estado de escalonamento
+// (WarnFlag/AssistFlag) e de quarentena por agente entre chamadas a
+// Evaluate.
+type AnomalyDetector interface {
+	// AddRule registra uma AnomalyRule adicional, avaliada depois das quatro
+	// padrão.
+	AddRule(rule AnomalyRule)
+
+	// AddSink registra um AnomalySink, chamado para cada HealthAnomaly
+	// encontrada em Evaluate.
+	AddSink(sink AnomalySink)
+
+	// Evaluate roda uma rodada de avaliação sobre healths, retornando as
+	// anomalias encontradas nesta rodada.
+	Evaluate(healths map[string]*AgentHealth) []HealthAnomaly
+
+	// IsQuarantined indica se agentName está atualmente em quarentena
+	// (unschedulable) — consultado por TaskManager.GetNextTask.
+	IsQuarantined(agentName string) bool
+}
+
+// defaultAnomalyDetector é a implementação padrão de AnomalyDetector,
+// retornada por NewAnomalyDetector.
+type defaultAnomalyDetector struct {
+	cfg AnomalyConfig
+
+	mu        sync.Mutex
+	baselines map[string]*agentBaseline
+	rules     []AnomalyRule
+	sinks     []AnomalySink
+}
+
+// NewAnomalyDetector cria um AnomalyDetector com os limiares de cfg. Use
+// DefaultAnomalyConfig() como ponto de partida.
+func NewAnomalyDetector(cfg AnomalyConfig) AnomalyDetector {
+	return &defaultAnomalyDetector{
+		cfg:       cfg,
+		baselines: make(map[string]*agentBaseline),
+	}
+}
+
+// AddRule implementa AnomalyDetector.
+func (d *defaultAnomalyDetector) AddRule(rule AnomalyRule) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rules = append(d.rules, rule)
+}
+
+// AddSink implementa AnomalyDetector.
+func (d *defaultAnomalyDetector) AddSink(sink AnomalySink) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sinks = append(d.sinks, sink)
+}
+
+// IsQuarantined implementa AnomalyDetector.
+func (d *defaultAnomalyDetector) IsQuarantined(agentName string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, ok := d.baselines[agentName]
+	return ok && b.quarantined
+}
+
+// Evaluate implementa AnomalyDetector, rodando as quatro checagens padrão
+// mais quaisquer AnomalyRule adicionais sobre cada agente em healths,
+// atualizando a baseline de ProcessingTime e o estado de escalonamento antes
+// de publicar nos sinks registrados.
+func (d *defaultAnomalyDetector) Evaluate(healths map[string]*AgentHealth) []HealthAnomaly {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	var all []HealthAnomaly
+
+	for name, health := range healths {
+		baseline, ok := d.baselines[name]
+		if !ok {
+			baseline = &agentBaseline{}
+			d.baselines[name] = baseline
+		}
+
+		anomalies := d.evaluateDefaults(name, health, baseline, now)
+		for _, rule := range d.rules {
+			anomalies = append(anomalies, rule.Evaluate(name, health, d.cfg)...)
+		}
+
+		if health.ProcessingTime > 0 {
+			baseline.observe(health.ProcessingTime, d.cfg.ProcessingBaselineAlpha)
+		}
+
+		if len(anomalies) > 0 {
+			baseline.consecutiveAnomalies++
+			baseline.warnFlag++
+		} else {
+			baseline.consecutiveAnomalies = 0
+		}
+
+		d.applyQuarantine(name, health, baseline, now)
+
+		for i := range anomalies {
+			anomalies[i].WarnFlag = baseline.warnFlag
+			anomalies[i].AssistFlag = baseline.assistFlag
+		}
+
+		for _, anomaly := range anomalies {
+			for _, sink := range d.sinks {
+				sink(anomaly)
+			}
+		}
+
+		all = append(all, anomalies...)
+	}
+
+	return all
+}
+
+// evaluateDefaults roda as quatro checagens padrão — HeartbeatMissed,
+// SuccessRateDrop, SlowProcessing e RepeatedError — sobre um único agente.
+// Chamado com d.mu já travado.
+func (d *defaultAnomalyDetector) evaluateDefaults(name string, health *AgentHealth, baseline *agentBaseline, now time.Time) []HealthAnomaly {
+	var anomalies []HealthAnomaly
+
+	if !health.LastHeartbeat.IsZero() && now.Sub(health.LastHeartbeat) > d.cfg.HeartbeatStaleAfter {
+		anomalies = append(anomalies, HealthAnomaly{
+			AgentName: name,
+			Category:  AnomalyHeartbeatMissed,
+			Detail:    fmt.Sprintf("último heartbeat há %s, acima do limiar de %s", now.Sub(health.LastHeartbeat), d.cfg.HeartbeatStaleAfter),
+			At:        now,
+		})
+	}
+
+	if health.SuccessRate < d.cfg.SuccessRateFloor {
+		anomalies = append(anomalies, HealthAnomaly{
+			AgentName: name,
+			Category:  AnomalySuccessRateDrop,
+			Detail:    fmt.Sprintf("success_rate %.2f abaixo do piso %.2f", health.SuccessRate, d.cfg.SuccessRateFloor),
+			At:        now,
+		})
+	}
+
+	if baseline.ready && health.ProcessingTime > baseline.mean+d.cfg.ProcessingStddevK*baseline.stddev() {
+		anomalies = append(anomalies, HealthAnomaly{
+			AgentName: name,
+			Category:  AnomalySlowProcessing,
+			Detail: fmt.Sprintf("processing_time %.2fs acima da baseline %.2fs + %.1f·stddev (%.2fs)",
+				health.ProcessingTime, baseline.mean, d.cfg.ProcessingStddevK, baseline.stddev()),
+			At: now,
+		})
+	}
+
+	if health.Error == nil {
+		baseline.lastErrorCode = ""
+		baseline.repeatedErrorCount = 0
+	} else {
+		// Agrupado por Code, não pela mensagem — duas falhas de timeout com
+		// textos diferentes (ex.: prazos distintos) ainda contam como o
+		// mesmo erro se repetindo, em vez de exigir correspondência exata de
+		// texto.
+		if health.Error.Code == baseline.lastErrorCode {
+			baseline.repeatedErrorCount++
+		} else {
+			baseline.lastErrorCode = health.Error.Code
+			baseline.repeatedErrorCount = 1
+		}
+
+		if baseline.repeatedErrorCount >= d.cfg.RepeatedErrorThreshold {
+			anomalies = append(anomalies, HealthAnomaly{
+				AgentName: name,
+				Category:  AnomalyRepeatedError,
+				Detail:    fmt.Sprintf("mesmo erro (%s) repetido %d vezes seguidas: %s", baseline.lastErrorCode, baseline.repeatedErrorCount, health.Error.Message),
+				At:        now,
+			})
+		}
+	}
+
+	return anomalies
+}
+
+// applyQuarantine decide se o agente entra ou sai de quarentena, chamado com
+// d.mu já travado. Entra após QuarantineAfter anomalias consecutivas; sai
+// depois que heartbeat e SuccessRate se mantêm dentro dos limiares por
+// RecoveryCooldown seguidos.
+func (d *defaultAnomalyDetector) applyQuarantine(name string, health *AgentHealth, baseline *agentBaseline, now time.Time) {
+	if !baseline.quarantined {
+		if d.cfg.QuarantineAfter > 0 && baseline.consecutiveAnomalies >= d.cfg.QuarantineAfter {
+			baseline.quarantined = true
+			baseline.assistFlag++
+			baseline.recoveringSince = time.Time{}
+			log.Printf("🚨 agente %s entrou em quarentena após %d anomalias consecutivas", name, baseline.consecutiveAnomalies)
+		}
+		return
+	}
+
+	recovered := now.Sub(health.LastHeartbeat) <= d.cfg.HeartbeatStaleAfter && health.SuccessRate >= d.cfg.SuccessRateFloor
+	if !recovered {
+		baseline.recoveringSince = time.Time{}
+		return
+	}
+
+	if baseline.recoveringSince.IsZero() {
+		baseline.recoveringSince = now
+		return
+	}
+
+	if now.Sub(baseline.recoveringSince) >= d.cfg.RecoveryCooldown {
+		baseline.quarantined = false
+		baseline.recoveringSince = time.Time{}
+		baseline.consecutiveAnomalies = 0
+		log.Printf("✅ agente %s saiu de quarentena após recuperação sustentada", name)
+	}
+}
+
+// RunAnomalyDetector roda, em loop até ctx ser cancelado, uma rodada de
+// detector.Evaluate a cada interval sobre o snapshot atual de AgentHealth de
+// tm — separado do ciclo de heartbeats de monitorHealthEvents para que o
+// detector possa ser trocado ou desligado independentemente.
+func RunAnomalyDetector(ctx context.Context, tm *TaskManager, detector AnomalyDetector, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			detector.Evaluate(tm.snapshotHealth())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// NewWebhookAnomalySink constrói um AnomalySink que faz POST do JSON de cada
+// HealthAnomaly para url, usando client (http.DefaultClient se nil) — a
+// forma mais simples de ligar anomalias a um sistema externo de paging.
+// Falhas de entrega apenas logam um aviso: um sink não pode bloquear ou
+// derrubar Evaluate.
+func NewWebhookAnomalySink(url string, client *http.Client) AnomalySink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return func(anomaly HealthAnomaly) {
+		body, err := json.Marshal(anomaly)
+		if err != nil {
+			log.Printf("⚠️ erro ao codificar anomalia para webhook: %v", err)
+			return
+		}
+
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			log.Printf("⚠️ erro ao enviar anomalia ao webhook %s: %v", url, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 400 {
+			log.Printf("⚠️ webhook de anomalia %s respondeu com status %d", url, resp.StatusCode)
+		}
+	}
+}
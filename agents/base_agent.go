@@ -0,0 +1,115 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// BaseAgent é a implementação mínima de agente sobre a qual agentes
+// especializados (ex.: CognitiveAgent) são construídos: identidade, limite de
+// rounds de execução e o ciclo Train/Validate comum a todo agente treinável.
+// Especializações embutem *BaseAgent e chamam a.BaseAgent.Train/Validate
+// antes de aplicar seus próprios ajustes.
+type BaseAgent struct {
+	ID          string
+	Name        string
+	Description string
+	MaxRounds   int
+}
+
+// NewBaseAgent cria um BaseAgent com os campos de identidade informados.
+func NewBaseAgent(id, name, description string, maxRounds int) *BaseAgent {
+	return &BaseAgent{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		MaxRounds:   maxRounds,
+	}
+}
+
+// GetMaxRounds retorna o número máximo de rounds de execução/treinamento
+// configurado para o agente.
+func (a *BaseAgent) GetMaxRounds() int {
+	return a.MaxRounds
+}
+
+// TrainingConfig parametriza uma rodada de treinamento sobre um BaseAgent.
+type TrainingConfig struct {
+	MaxRounds       int           // Limite de rounds nesta rodada; 0 ou maior que o do agente usa o MaxRounds do próprio agente
+	TrainingTimeout time.Duration // Tempo máximo tolerado para a rodada inteira
+	ValidationRatio float64       // Fração dos dados reservada para validação
+	MinAccuracy     float64       // Acurácia mínima aceitável ao final do treinamento
+	BatchSize       int           // Tamanho do lote usado em cada round
+	LearningRate    float64       // Taxa de aprendizado inicial
+	UseHistorical   bool          // Se o histórico de respostas do agente deve influenciar o ajuste de parâmetros
+}
+
+// TrainingMetrics reporta o resultado de uma rodada de treinamento.
+type TrainingMetrics struct {
+	RoundsExecuted int
+	Accuracy       float64
+	Loss           float64
+	StartTime      time.Time
+	EndTime        time.Time
+	Errors         []string
+}
+
+// Train executa o treinamento base: roda até MaxRounds rounds (respeitando
+// TrainingTimeout e o cancelamento do contexto) e devolve as métricas
+// coletadas. Especializações como CognitiveAgent.Train chamam este método
+// antes de aplicar seus próprios ajustes de parâmetros.
+func (a *BaseAgent) Train(ctx context.Context, config TrainingConfig) (*TrainingMetrics, error) {
+	metrics := &TrainingMetrics{StartTime: time.Now()}
+
+	maxRounds := a.MaxRounds
+	if config.MaxRounds > 0 && config.MaxRounds < maxRounds {
+		maxRounds = config.MaxRounds
+	}
+
+	if config.TrainingTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.TrainingTimeout)
+		defer cancel()
+	}
+
+	for round := 0; round < maxRounds; round++ {
+		select {
+		case <-ctx.Done():
+			metrics.Errors = append(metrics.Errors, ctx.Err().Error())
+			metrics.EndTime = time.Now()
+			return metrics, ctx.Err()
+		default:
+			metrics.RoundsExecuted++
+		}
+	}
+
+	metrics.EndTime = time.Now()
+	return metrics, nil
+}
+
+// Validate confirma que o agente está em condições de receber tarefas:
+// identidade preenchida e limite de rounds configurado.
+func (a *BaseAgent) Validate(ctx context.Context) error {
+	if a.ID == "" {
+		return fmt.Errorf("agente sem ID")
+	}
+	if a.MaxRounds <= 0 {
+		return fmt.Errorf("número máximo de rounds inválido: %d", a.MaxRounds)
+	}
+	return nil
+}
+
+// SaveState grava o estado do agente como JSON no caminho informado.
+func (a *BaseAgent) SaveState(path string) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar estado do agente: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("erro ao gravar estado do agente em %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,486 @@
+package agents
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// Broker abstrai o transporte de mensagens usado por TaskManager para
+// publicar tarefas e eventos de saúde e para consumi-los de volta. A
+// abstração existe para que TaskManager possa ser exercitado em testes com
+// InMemoryBroker, sem depender de um RabbitMQ real, e para centralizar a
+// lógica de reconexão do AMQPBroker num único lugar.
+type Broker interface {
+	// DeclareExchange garante que o exchange name, do tipo kind (ex.:
+	// "topic"), existe antes de ser usado por Publish ou Subscribe.
+	DeclareExchange(name, kind string) error
+
+	// Publish envia body para exchange com a routing key informada.
+	Publish(exchange, routingKey string, body []byte) error
+
+	// Subscribe vincula uma fila a exchange usando bindingKey (ex.:
+	// "health.*") e retorna um canal com o corpo de cada mensagem recebida.
+	// O canal é fechado quando o Broker é fechado.
+	Subscribe(exchange, bindingKey string) (<-chan []byte, error)
+
+	// Alive informa se o Broker está, no momento, apto a publicar e
+	// consumir mensagens.
+	Alive() bool
+
+	// Close libera os recursos do Broker. Subscribers em andamento têm seus
+	// canais fechados.
+	Close() error
+}
+
+// BrokerConfig configura um AMQPBroker.
+type BrokerConfig struct {
+	// URL, se não-vazio, é usado como está para amqp.Dial (ou amqp.DialTLS,
+	// quando TLSConfig não é nil), ignorando Host/Port/Username/Password.
+	URL string
+
+	Host     string
+	Port     int
+	Username string
+	Password string
+
+	// TLSConfig, se não-nil, faz o AMQPBroker discar com amqps:// usando
+	// essa configuração em vez de uma conexão em texto claro.
+	TLSConfig *tls.Config
+
+	// Prefetch limita quantas mensagens não confirmadas o canal aceita de
+	// uma vez (ch.Qos). Zero desabilita o limite.
+	Prefetch int
+
+	// ReconnectMinBackoff e ReconnectMaxBackoff definem os limites do
+	// backoff exponencial usado entre tentativas de reconexão após uma
+	// queda de conexão ou canal. O backoff dobra a cada tentativa, a
+	// partir de ReconnectMinBackoff, até ReconnectMaxBackoff.
+	ReconnectMinBackoff time.Duration
+	ReconnectMaxBackoff time.Duration
+}
+
+// DefaultBrokerConfig retorna a configuração usada historicamente por
+// NewTaskManager: RabbitMQ local, sem TLS, sem limite de prefetch, com
+// backoff de reconexão entre 1s e 30s.
+func DefaultBrokerConfig() BrokerConfig {
+	return BrokerConfig{
+		Host:                RABBITMQ_HOST,
+		Port:                RABBITMQ_PORT,
+		Username:            "guest",
+		Password:            "guest",
+		ReconnectMinBackoff: 1 * time.Second,
+		ReconnectMaxBackoff: 30 * time.Second,
+	}
+}
+
+func (c BrokerConfig) dialURL() string {
+	if c.URL != "" {
+		return c.URL
+	}
+	scheme := "amqp"
+	if c.TLSConfig != nil {
+		scheme = "amqps"
+	}
+	return fmt.Sprintf("%s://%s:%s@%s:%d/", scheme, c.Username, c.Password, c.Host, c.Port)
+}
+
+// amqpSubscription lembra como recriar um Subscribe após uma reconexão.
+type amqpSubscription struct {
+	exchange   string
+	bindingKey string
+	out        chan []byte
+}
+
+// AMQPBroker implementa Broker sobre uma conexão RabbitMQ real, com
+// reconexão automática e backoff exponencial: uma queda de conexão ou canal
+// não derruba mais silenciosamente monitorHealthEvents, apenas redeclara
+// exchanges e re-assina os Subscribe em andamento assim que a conexão volta.
+type AMQPBroker struct {
+	cfg BrokerConfig
+
+	mu        sync.RWMutex
+	conn      *amqp.Connection
+	ch        *amqp.Channel
+	exchanges []struct{ name, kind string }
+	subs      []*amqpSubscription
+	closed    bool
+}
+
+// NewAMQPBroker disca o RabbitMQ descrito por cfg e inicia a goroutine de
+// vigilância da conexão.
+func NewAMQPBroker(cfg BrokerConfig) (*AMQPBroker, error) {
+	b := &AMQPBroker{cfg: cfg}
+
+	if err := b.connect(); err != nil {
+		return nil, err
+	}
+
+	go b.watch()
+
+	return b, nil
+}
+
+func (b *AMQPBroker) connect() error {
+	var conn *amqp.Connection
+	var err error
+	if b.cfg.TLSConfig != nil {
+		conn, err = amqp.DialTLS(b.cfg.dialURL(), b.cfg.TLSConfig)
+	} else {
+		conn, err = amqp.Dial(b.cfg.dialURL())
+	}
+	if err != nil {
+		return fmt.Errorf("falha ao conectar ao RabbitMQ: %v", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("falha ao criar canal: %v", err)
+	}
+
+	if b.cfg.Prefetch > 0 {
+		if err := ch.Qos(b.cfg.Prefetch, 0, false); err != nil {
+			ch.Close()
+			conn.Close()
+			return fmt.Errorf("falha ao configurar prefetch: %v", err)
+		}
+	}
+
+	b.mu.Lock()
+	b.conn = conn
+	b.ch = ch
+	b.mu.Unlock()
+
+	return nil
+}
+
+// watch observa o fechamento da conexão corrente e reconecta com backoff
+// exponencial, redeclarando exchanges e re-assinando subscriptions ativas.
+func (b *AMQPBroker) watch() {
+	for {
+		b.mu.RLock()
+		conn := b.conn
+		closed := b.closed
+		b.mu.RUnlock()
+
+		if closed {
+			return
+		}
+
+		closeCh := conn.NotifyClose(make(chan *amqp.Error, 1))
+		<-closeCh
+
+		b.mu.RLock()
+		closed = b.closed
+		b.mu.RUnlock()
+		if closed {
+			return
+		}
+
+		log.Printf("⚠️ conexão AMQP caiu, iniciando reconexão com backoff exponencial")
+		b.reconnect()
+	}
+}
+
+func (b *AMQPBroker) reconnect() {
+	backoff := b.cfg.ReconnectMinBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	maxBackoff := b.cfg.ReconnectMaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+
+	for {
+		b.mu.RLock()
+		closed := b.closed
+		b.mu.RUnlock()
+		if closed {
+			return
+		}
+
+		if err := b.connect(); err != nil {
+			log.Printf("⚠️ falha ao reconectar ao RabbitMQ, tentando novamente em %s: %v", backoff, err)
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+
+		if err := b.restoreState(); err != nil {
+			log.Printf("⚠️ falha ao restaurar estado do AMQPBroker após reconexão: %v", err)
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+
+		log.Printf("✅ reconectado ao RabbitMQ")
+		return
+	}
+}
+
+// restoreState redeclara os exchanges conhecidos e re-assina todas as
+// subscriptions ativas sobre a conexão recém-estabelecida.
+func (b *AMQPBroker) restoreState() error {
+	b.mu.RLock()
+	exchanges := append([]struct{ name, kind string }(nil), b.exchanges...)
+	subs := append([]*amqpSubscription(nil), b.subs...)
+	b.mu.RUnlock()
+
+	for _, ex := range exchanges {
+		if err := b.declareExchangeLocked(ex.name, ex.kind); err != nil {
+			return err
+		}
+	}
+
+	for _, sub := range subs {
+		if err := b.bindAndConsume(sub.exchange, sub.bindingKey, sub.out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *AMQPBroker) declareExchangeLocked(name, kind string) error {
+	b.mu.RLock()
+	ch := b.ch
+	b.mu.RUnlock()
+
+	return ch.ExchangeDeclare(name, kind, QUEUE_DURABLE, false, false, false, nil)
+}
+
+// DeclareExchange declara o exchange e o registra para ser redeclarado
+// automaticamente após uma reconexão.
+func (b *AMQPBroker) DeclareExchange(name, kind string) error {
+	if err := b.declareExchangeLocked(name, kind); err != nil {
+		return fmt.Errorf("falha ao declarar exchange %s: %v", name, err)
+	}
+
+	b.mu.Lock()
+	b.exchanges = append(b.exchanges, struct{ name, kind string }{name, kind})
+	b.mu.Unlock()
+
+	return nil
+}
+
+// Publish publica body em exchange com a routing key informada, persistente.
+func (b *AMQPBroker) Publish(exchange, routingKey string, body []byte) error {
+	b.mu.RLock()
+	ch := b.ch
+	b.mu.RUnlock()
+
+	err := ch.Publish(
+		exchange,
+		routingKey,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType:  "application/json",
+			Body:         body,
+			DeliveryMode: MESSAGE_PERSISTENT,
+		})
+	if err != nil {
+		return fmt.Errorf("erro ao publicar em %s: %v", exchange, err)
+	}
+	return nil
+}
+
+func (b *AMQPBroker) bindAndConsume(exchange, bindingKey string, out chan []byte) error {
+	b.mu.RLock()
+	ch := b.ch
+	b.mu.RUnlock()
+
+	q, err := ch.QueueDeclare("", QUEUE_DURABLE, true, true, QUEUE_NO_WAIT, nil)
+	if err != nil {
+		return fmt.Errorf("falha ao declarar fila para %s: %v", bindingKey, err)
+	}
+
+	if err := ch.QueueBind(q.Name, bindingKey, exchange, false, nil); err != nil {
+		return fmt.Errorf("falha ao fazer binding de %s em %s: %v", bindingKey, exchange, err)
+	}
+
+	msgs, err := ch.Consume(q.Name, "", true, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("falha ao consumir fila de %s: %v", bindingKey, err)
+	}
+
+	go func() {
+		for msg := range msgs {
+			out <- msg.Body
+		}
+
+		// msgs também fecha numa queda de conexão, caso em que watch
+		// re-assina out; só Close encerra a subscription de vez.
+		b.mu.RLock()
+		closed := b.closed
+		b.mu.RUnlock()
+		if closed {
+			close(out)
+		}
+	}()
+
+	return nil
+}
+
+// Subscribe vincula uma fila exclusiva a exchange usando bindingKey e
+// registra a subscription para ser recriada automaticamente após uma
+// reconexão.
+func (b *AMQPBroker) Subscribe(exchange, bindingKey string) (<-chan []byte, error) {
+	out := make(chan []byte)
+
+	if err := b.bindAndConsume(exchange, bindingKey, out); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	b.subs = append(b.subs, &amqpSubscription{exchange: exchange, bindingKey: bindingKey, out: out})
+	b.mu.Unlock()
+
+	return out, nil
+}
+
+// Alive informa se a conexão AMQP corrente continua aberta.
+func (b *AMQPBroker) Alive() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.conn != nil && !b.conn.IsClosed()
+}
+
+// Close encerra a conexão AMQP e impede novas tentativas de reconexão.
+func (b *AMQPBroker) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	ch := b.ch
+	conn := b.conn
+	b.mu.Unlock()
+
+	if ch != nil {
+		ch.Close()
+	}
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
+
+// InMemoryBroker implementa Broker inteiramente em memória, sem nenhuma
+// dependência externa, para uso em testes unitários de TaskManager.
+type InMemoryBroker struct {
+	mu     sync.RWMutex
+	subs   map[string][]*inMemorySub
+	closed bool
+}
+
+type inMemorySub struct {
+	bindingKey string
+	out        chan []byte
+}
+
+// NewInMemoryBroker cria um InMemoryBroker vazio.
+func NewInMemoryBroker() *InMemoryBroker {
+	return &InMemoryBroker{subs: make(map[string][]*inMemorySub)}
+}
+
+// DeclareExchange é um no-op no InMemoryBroker: exchanges não precisam ser
+// declarados antes do uso.
+func (b *InMemoryBroker) DeclareExchange(name, kind string) error {
+	return nil
+}
+
+// Publish entrega body a todas as subscriptions de exchange cuja bindingKey
+// combine com routingKey, seguindo a semântica simplificada de topic
+// exchanges do AMQP (segmentos separados por ".", "*" casa um segmento).
+func (b *InMemoryBroker) Publish(exchange, routingKey string, body []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return fmt.Errorf("broker fechado")
+	}
+
+	for _, sub := range b.subs[exchange] {
+		if topicMatch(sub.bindingKey, routingKey) {
+			sub.out <- body
+		}
+	}
+	return nil
+}
+
+// Subscribe registra uma nova subscription em memória para exchange/bindingKey.
+func (b *InMemoryBroker) Subscribe(exchange, bindingKey string) (<-chan []byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := make(chan []byte, 16)
+	b.subs[exchange] = append(b.subs[exchange], &inMemorySub{bindingKey: bindingKey, out: out})
+	return out, nil
+}
+
+// Alive é true para o InMemoryBroker até que Close seja chamado.
+func (b *InMemoryBroker) Alive() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return !b.closed
+}
+
+// Close marca o broker como fechado e fecha os canais de todas as
+// subscriptions; publicações subsequentes falham.
+func (b *InMemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	for _, subs := range b.subs {
+		for _, sub := range subs {
+			close(sub.out)
+		}
+	}
+	return nil
+}
+
+// topicMatch implementa a semântica de binding key do AMQP topic exchange
+// necessária pelos padrões usados neste pacote ("health.*", "task.quiz"):
+// segmentos separados por ".", onde "*" no binding casa exatamente um
+// segmento de routingKey.
+func topicMatch(bindingKey, routingKey string) bool {
+	bindingParts := splitDot(bindingKey)
+	routingParts := splitDot(routingKey)
+
+	if len(bindingParts) != len(routingParts) {
+		return false
+	}
+
+	for i, part := range bindingParts {
+		if part != "*" && part != routingParts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func splitDot(s string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '.' {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, s[start:])
+	return parts
+}
@@ -0,0 +1,83 @@
+package agents
+
+import "testing"
+
+// TestInMemoryBrokerPublishSubscribe cobre o caminho feliz do InMemoryBroker:
+// uma subscription recebe as mensagens publicadas em seu exchange cuja
+// routing key case com seu bindingKey.
+func TestInMemoryBrokerPublishSubscribe(t *testing.T) {
+	b := NewInMemoryBroker()
+
+	out, err := b.Subscribe(EXCHANGE_TASK, "task.quiz")
+	if err != nil {
+		t.Fatalf("Subscribe retornou erro: %v", err)
+	}
+
+	if err := b.Publish(EXCHANGE_TASK, "task.quiz", []byte("oi")); err != nil {
+		t.Fatalf("Publish retornou erro: %v", err)
+	}
+
+	select {
+	case msg := <-out:
+		if string(msg) != "oi" {
+			t.Fatalf("mensagem recebida = %q, esperado %q", msg, "oi")
+		}
+	default:
+		t.Fatal("nenhuma mensagem recebida no canal da subscription")
+	}
+}
+
+// TestInMemoryBrokerTopicMatchWildcard confirma que "*" no bindingKey casa
+// exatamente um segmento da routing key, igual a um topic exchange do AMQP.
+func TestInMemoryBrokerTopicMatchWildcard(t *testing.T) {
+	b := NewInMemoryBroker()
+
+	out, err := b.Subscribe(EXCHANGE_HEALTH, "health.*")
+	if err != nil {
+		t.Fatalf("Subscribe retornou erro: %v", err)
+	}
+
+	if err := b.Publish(EXCHANGE_HEALTH, "health.agent1", []byte("saudavel")); err != nil {
+		t.Fatalf("Publish retornou erro: %v", err)
+	}
+	if err := b.Publish(EXCHANGE_HEALTH, "health.agent1.extra", []byte("nao deveria casar")); err != nil {
+		t.Fatalf("Publish retornou erro: %v", err)
+	}
+
+	select {
+	case msg := <-out:
+		if string(msg) != "saudavel" {
+			t.Fatalf("mensagem recebida = %q, esperado %q", msg, "saudavel")
+		}
+	default:
+		t.Fatal("nenhuma mensagem recebida para a routing key que deveria casar")
+	}
+
+	select {
+	case msg := <-out:
+		t.Fatalf("routing key com segmento extra não deveria casar, recebeu %q", msg)
+	default:
+	}
+}
+
+// TestInMemoryBrokerPublishAfterCloseFails confirma que publicações após
+// Close falham em vez de silenciosamente não entregar a mensagem.
+func TestInMemoryBrokerPublishAfterCloseFails(t *testing.T) {
+	b := NewInMemoryBroker()
+
+	if !b.Alive() {
+		t.Fatal("broker recém-criado deveria estar Alive")
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close retornou erro: %v", err)
+	}
+
+	if b.Alive() {
+		t.Fatal("broker fechado não deveria estar Alive")
+	}
+
+	if err := b.Publish(EXCHANGE_TASK, "task.quiz", []byte("oi")); err == nil {
+		t.Fatal("Publish após Close deveria retornar erro")
+	}
+}
@@ -3,9 +3,18 @@ package agents
 import (
 	"context"
 	"fmt"
+	"log"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"HiveMindForge/agents/agentrpc"
+	"HiveMindForge/agents/idents"
 	"HiveMindForge/agents/memory"
+	"HiveMindForge/agents/scheduler"
+
+	"github.com/go-redis/redis/v8"
 )
 
 // CognitiveAgent representa um agente cognitivo que pode executar tarefas específicas
@@ -32,6 +41,51 @@ type CognitiveAgent struct {
 	healthTicker  *time.Ticker
 	metricsTicker *time.Ticker
 	ctx           context.Context
+
+	// Campos usados para publicar heartbeats no TargetCache do orquestrador
+	identsClient  *redis.Client
+	inFlightTasks int
+	heartbeatErrs int
+
+	// healthChecks mantém as HealthCheck registradas para este agente; seu
+	// estado agregado é publicado em EXCHANGE_HEALTH por StartHealthReporting.
+	healthChecks *HealthCheckRegistry
+
+	// rpcClient é opcional, plugado via SetAgentRPCClient pelo orquestrador
+	// após RegisterAgent: quando presente, PushMetricsRPC/PullTaskRPC usam o
+	// AgentAPI tipado de agentrpc em vez das filas JSON sem schema.
+	rpcClient agentrpc.AgentAPIClient
+
+	// Scripts e Healthchecks são configurados via RegisterScripts e
+	// RegisterHealthchecks; ver scripts.go.
+	Scripts      []AgentScript
+	Healthchecks []Healthcheck
+
+	// Attributes rotula o host onde esta instância roda (ex.: "region",
+	// "gpu", "datacenter"), publicado no Heartbeat e consultado pelo
+	// scheduler do orquestrador ao posicionar clones futuros. Preenchido por
+	// OrchestratorInfrastructureAgent.placeInstance após cada scale-out.
+	Attributes map[string]string
+
+	// PlacementConstraints e SpreadTarget guiam o scheduler do orquestrador
+	// ao escolher onde colocar o próximo clone deste agente; SpreadAttribute
+	// é o atributo (ex.: "datacenter") sobre o qual SpreadTarget distribui.
+	// Ver agents/scheduler.
+	PlacementConstraints scheduler.PlacementConstraints
+	SpreadAttribute      string
+	SpreadTarget         scheduler.SpreadTarget
+
+	scriptsLock     sync.RWMutex
+	blockingScripts int32 // scripts com StartBlocksLogin em execução; ver Validate
+	cronSched       *cronScheduler
+
+	healthStatesLock sync.RWMutex
+	healthStates     map[string]HealthState
+
+	// stopOnce torna Stop idempotente: chamadas repetidas (ex.: scaleOut
+	// seguido de um shutdown do orquestrador) não tentam fechar stopChan
+	// duas vezes.
+	stopOnce sync.Once
 }
 
 // NewCognitiveAgent cria uma nova instância de CognitiveAgent
@@ -62,6 +116,32 @@ func NewCognitiveAgent(id, name, description string, maxRounds int, model string
 	}
 }
 
+// Clone cria uma cópia independente do agente cognitivo, usada por
+// OrchestratorInfrastructureAgent.scaleOut ao instanciar uma nova réplica de
+// um agente sobrecarregado. Preserva identidade e parâmetros de
+// configuração, mas começa com estado de execução (histórico, estatísticas,
+// canais, tickers) zerado — análogo a Agent.Clone.
+func (a *CognitiveAgent) Clone() *CognitiveAgent {
+	clone := NewCognitiveAgent(a.ID, a.Name, a.Description, a.MaxRounds, a.Model, a.Role, a.Goal, a.memoryManager)
+	clone.Temperature = a.Temperature
+	clone.MaxTokens = a.MaxTokens
+	clone.ContextWindow = a.ContextWindow
+	clone.LearningRate = a.LearningRate
+	clone.AllowDelegation = a.AllowDelegation
+	clone.Backstory = a.Backstory
+
+	clone.Attributes = make(map[string]string, len(a.Attributes))
+	for k, v := range a.Attributes {
+		clone.Attributes[k] = v
+	}
+
+	clone.PlacementConstraints = a.PlacementConstraints
+	clone.SpreadAttribute = a.SpreadAttribute
+	clone.SpreadTarget = a.SpreadTarget
+
+	return clone
+}
+
 // Train implementa o treinamento específico para o agente cognitivo
 func (a *CognitiveAgent) Train(ctx context.Context, config TrainingConfig) (*TrainingMetrics, error) {
 	// Primeiro executa o treinamento base
@@ -108,8 +188,10 @@ func (a *CognitiveAgent) Train(ctx context.Context, config TrainingConfig) (*Tra
 	return metrics, nil
 }
 
-// Remember busca memórias relacionadas a um conjunto de tags
-func (a *CognitiveAgent) Remember(ctx context.Context, tags []string) ([]*memory.Memory, error) {
+// Remember busca memórias relacionadas a um conjunto de tags. Cada resultado
+// carrega uma lease viva; o chamador deve chamar Release quando terminar de
+// usá-lo para liberar a memória para consolidação/poda.
+func (a *CognitiveAgent) Remember(ctx context.Context, tags []string) ([]*memory.MemoryRef, error) {
 	return a.memoryManager.SearchMemories(ctx, a.ID, tags)
 }
 
@@ -196,6 +278,13 @@ func (a *CognitiveAgent) Validate(ctx context.Context) error {
 		return err
 	}
 
+	// Enquanto algum script com StartBlocksLogin ainda está rodando (ex.: um
+	// script de setup disparado por RunOnStart), o agente não deve começar a
+	// processar tarefas.
+	if atomic.LoadInt32(&a.blockingScripts) > 0 {
+		return fmt.Errorf("aguardando término de script de inicialização bloqueante")
+	}
+
 	// Validações específicas do agente cognitivo
 	if a.Temperature <= 0 {
 		return fmt.Errorf("temperatura inválida: %v", a.Temperature)
@@ -284,3 +373,163 @@ func (a *CognitiveAgent) GetBackstory() string {
 func (a *CognitiveAgent) SetBackstory(backstory string) {
 	a.Backstory = backstory
 }
+
+// RegisterHeartbeat começa a publicar heartbeats periódicos no Redis sob
+// `ident:{agent_id}`, com TTL de 3x o intervalo, para que o TargetCache do
+// orquestrador descubra este agente mesmo rodando em outro processo/pod.
+func (a *CognitiveAgent) RegisterHeartbeat(ctx context.Context, interval time.Duration) error {
+	if a.identsClient == nil {
+		a.identsClient = redis.NewClient(&redis.Options{
+			Addr: fmt.Sprintf("%s:%d", REDIS_HOST, REDIS_PORT),
+		})
+	}
+
+	if err := a.identsClient.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("erro ao conectar ao Redis de idents: %v", err)
+	}
+
+	idents.StartPublishing(ctx, a.identsClient, interval, a.stopChan, func() *idents.Heartbeat {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+
+		return &idents.Heartbeat{
+			AgentID:       a.ID,
+			Role:          a.Role,
+			Version:       a.Model,
+			MemoryUsage:   mem.Alloc,
+			InFlightTasks: a.inFlightTasks,
+			ErrorCount:    a.heartbeatErrs,
+			Attributes:    a.Attributes,
+		}
+	})
+
+	return nil
+}
+
+// SetTaskManager associa o TaskManager usado por StartHealthReporting para
+// publicar a saúde agregada do agente em EXCHANGE_HEALTH.
+func (a *CognitiveAgent) SetTaskManager(tm *TaskManager) {
+	a.taskManager = tm
+}
+
+// SetAgentRPCClient associa o cliente usado por PushMetricsRPC e PullTaskRPC
+// para falar com o AgentAPI da réplica do orquestrador dona deste agente.
+func (a *CognitiveAgent) SetAgentRPCClient(client agentrpc.AgentAPIClient) {
+	a.rpcClient = client
+}
+
+// PushMetricsRPC envia samples ao AgentAPI via rpcClient, substituindo a
+// publicação em filas JSON ad-hoc por métricas. É um no-op se nenhum
+// rpcClient foi associado via SetAgentRPCClient.
+func (a *CognitiveAgent) PushMetricsRPC(ctx context.Context, samples []agentrpc.MetricSample) error {
+	if a.rpcClient == nil {
+		return nil
+	}
+
+	_, err := a.rpcClient.PushMetrics(ctx, &agentrpc.PushMetricsRequest{
+		AgentID: a.ID,
+		Samples: samples,
+	})
+	return err
+}
+
+// PullTaskRPC pede ao AgentAPI, via rpcClient, até uma tarefa atribuível a
+// este agente, devolvendo nil se nenhum rpcClient foi associado ou se não há
+// tarefa pendente.
+func (a *CognitiveAgent) PullTaskRPC(ctx context.Context) (*agentrpc.Task, error) {
+	if a.rpcClient == nil {
+		return nil, nil
+	}
+
+	resp, err := a.rpcClient.PullTasks(ctx, &agentrpc.PullTasksRequest{AgentID: a.ID, MaxTasks: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Tasks) == 0 {
+		return nil, nil
+	}
+	return &resp.Tasks[0], nil
+}
+
+// RegisterHealthCheck adiciona uma HealthCheck ao agente, criando seu
+// HealthCheckRegistry sob demanda na primeira chamada, e inicia a goroutine
+// que a executa a cada probe.Interval.
+func (a *CognitiveAgent) RegisterHealthCheck(ctx context.Context, probe *HealthCheck) {
+	if a.healthChecks == nil {
+		a.healthChecks = NewHealthCheckRegistry()
+	}
+	a.healthChecks.RegisterProbe(ctx, probe)
+}
+
+// AggregateHealth retorna o pior estado entre as HealthCheck registradas
+// para este agente. Um agente sem sondas registradas é considerado
+// ProbeHealthy.
+func (a *CognitiveAgent) AggregateHealth() ProbeState {
+	if a.healthChecks == nil {
+		return ProbeHealthy
+	}
+	return a.healthChecks.Aggregate()
+}
+
+// StartHealthReporting publica periodicamente, em EXCHANGE_HEALTH, o estado
+// agregado e por sonda das HealthCheck deste agente junto com o AgentHealth
+// existente, para que TaskManager.GetNextTask recuse atribuir tarefas a
+// agentes Unhealthy.
+func (a *CognitiveAgent) StartHealthReporting(ctx context.Context, interval time.Duration) {
+	if a.taskManager == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				health := &AgentHealth{
+					AgentName:     a.Name,
+					LastHeartbeat: time.Now(),
+					ProbeState:    a.AggregateHealth(),
+				}
+				if a.healthChecks != nil {
+					health.Probes = a.healthChecks.Snapshot()
+				}
+
+				if err := a.taskManager.EmitHealthSignal(health); err != nil {
+					log.Printf("⚠️ falha ao publicar saúde agregada do agente %s: %v", a.Name, err)
+				}
+			case <-ctx.Done():
+				return
+			case <-a.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop encerra as goroutines em background deste agente (RegisterHeartbeat,
+// StartHealthReporting), fechando stopChan. Idempotente: chamadas repetidas
+// são no-ops, já que scaleOut pode clonar agentes cujo Stop acaba sendo
+// chamado mais de uma vez durante o desligamento do orquestrador.
+func (a *CognitiveAgent) Stop() {
+	a.stopOnce.Do(func() {
+		close(a.stopChan)
+
+		if a.healthTicker != nil {
+			a.healthTicker.Stop()
+		}
+		if a.metricsTicker != nil {
+			a.metricsTicker.Stop()
+		}
+		if a.identsClient != nil {
+			a.identsClient.Close()
+		}
+
+		a.runStopScripts()
+
+		if a.cronSched != nil {
+			a.cronSched.Stop()
+		}
+	})
+}
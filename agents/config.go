@@ -1,13 +1,16 @@
 package agents
 
+import "time"
+
 // Configurações do RabbitMQ
 const (
 	RABBITMQ_HOST = "localhost"
 	RABBITMQ_PORT = 5672
 
 	// Exchanges
-	EXCHANGE_HEALTH = "health_events"
-	EXCHANGE_TASK   = "task_events"
+	EXCHANGE_HEALTH    = "health_events"
+	EXCHANGE_TASK      = "task_events"
+	EXCHANGE_AGENT_RPC = "agent_rpc" // usado pelo shim de agents/agentrpc
 
 	// Filas
 	QUEUE_HEALTH_MONITOR = "health_monitor"
@@ -24,11 +27,28 @@ const (
 	MESSAGE_PERSISTENT = 2 // DeliveryMode 2 = persistente
 )
 
-// Configurações de escalabilidade
+// Configurações de escalabilidade. TASKS_THRESHOLD e ERROR_THRESHOLD são
+// comparados contra SystemMetrics.TasksPerAgent/ErrorCount (ambos int), e
+// SCALE_COOLDOWN contra time.Since(...) em ScaleSystem — por isso os dois
+// primeiros são constantes inteiras e SCALE_COOLDOWN é um time.Duration, em
+// vez de valores fracionários ou segundos soltos.
+const (
+	CPU_THRESHOLD    = 80.0 // Porcentagem de uso de CPU
+	MEMORY_THRESHOLD = 85.0 // Porcentagem de uso de memória
+	TASKS_THRESHOLD  = 10   // Número máximo de tarefas por agente
+	ERROR_THRESHOLD  = 5    // Número máximo de erros antes de escalar
+
+	// SCALE_COOLDOWN é o tempo mínimo de espera entre operações de escala.
+	SCALE_COOLDOWN = 5 * time.Minute
+)
+
+// Configurações do Redis usado para o keyspace de idents (heartbeats)
 const (
-	CPU_THRESHOLD    = 80.0 // 80% de uso de CPU
-	MEMORY_THRESHOLD = 85.0 // 85% de uso de memória
-	TASKS_THRESHOLD  = 100  // 100 tarefas na fila
-	ERROR_THRESHOLD  = 0.05 // 5% de taxa de erro
-	SCALE_COOLDOWN   = 300  // 5 minutos de cooldown entre escalas
+	REDIS_HOST             = "localhost"
+	REDIS_PORT             = 6379
+	DEFAULT_HEARTBEAT_TICK = 10 // segundos entre heartbeats
 )
+
+// METRICS_ADDR é o endereço onde o ObserverInfrastructureAgent expõe o
+// endpoint /metrics para raspagem por um Prometheus externo.
+const METRICS_ADDR = ":9090"
@@ -0,0 +1,119 @@
+// Package hashring implementa um anel de hash consistente usado para
+// distribuir tarefas e chaves de memória entre réplicas de agentes
+// cognitivos, seguindo o mesmo desenho do pacote alert/naming/hashring
+// do Nightingale.
+package hashring
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultVirtualNodes é o número de nós virtuais criados por membro quando
+// nenhum valor é informado explicitamente a NewHashRing.
+const DefaultVirtualNodes = 500
+
+// HashRing distribui chaves entre um conjunto de membros (agentes) usando
+// hashing consistente com nós virtuais, para manter o balanceamento quando
+// membros entram ou saem do anel.
+type HashRing struct {
+	mu           sync.RWMutex
+	virtualNodes int
+	hashes       []uint32
+	hashToMember map[uint32]string
+	members      map[string]bool
+}
+
+// NewHashRing cria um HashRing vazio. Se virtualNodes for <= 0, usa
+// DefaultVirtualNodes.
+func NewHashRing(virtualNodes int) *HashRing {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+
+	return &HashRing{
+		virtualNodes: virtualNodes,
+		hashToMember: make(map[uint32]string),
+		members:      make(map[string]bool),
+	}
+}
+
+// Add inclui um membro no anel, criando seus nós virtuais. Não faz nada se
+// o membro já estiver presente.
+func (r *HashRing) Add(member string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.members[member] {
+		return
+	}
+	r.members[member] = true
+
+	for i := 0; i < r.virtualNodes; i++ {
+		h := hashKey(member + "#" + strconv.Itoa(i))
+		r.hashToMember[h] = member
+		r.hashes = append(r.hashes, h)
+	}
+
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+}
+
+// Remove retira um membro e todos os seus nós virtuais do anel.
+func (r *HashRing) Remove(member string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.members[member] {
+		return
+	}
+	delete(r.members, member)
+
+	filtered := r.hashes[:0]
+	for _, h := range r.hashes {
+		if r.hashToMember[h] == member {
+			delete(r.hashToMember, h)
+			continue
+		}
+		filtered = append(filtered, h)
+	}
+	r.hashes = filtered
+}
+
+// OwnerOf retorna o membro responsável por uma chave, escolhendo o primeiro
+// nó virtual cujo hash seja >= ao hash da chave (voltando ao início do anel
+// quando necessário). Retorna ok=false se o anel estiver vazio.
+func (r *HashRing) OwnerOf(key string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.hashes) == 0 {
+		return "", false
+	}
+
+	h := hashKey(key)
+	idx := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if idx == len(r.hashes) {
+		idx = 0
+	}
+
+	return r.hashToMember[r.hashes[idx]], true
+}
+
+// Members retorna os membros atualmente presentes no anel.
+func (r *HashRing) Members() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]string, 0, len(r.members))
+	for m := range r.members {
+		out = append(out, m)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func hashKey(s string) uint32 {
+	return crc32.ChecksumIEEE([]byte(s))
+}
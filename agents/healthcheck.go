@@ -0,0 +1,200 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// ProbeState representa o estado de uma HealthCheck individual ou o agregado
+// de todas as sondas de um agente.
+type ProbeState string
+
+const (
+	ProbeInitializing ProbeState = "initializing"
+	ProbeHealthy      ProbeState = "healthy"
+	ProbeUnhealthy    ProbeState = "unhealthy"
+)
+
+// HealthCheck é uma sonda nomeada executada periodicamente por um agente,
+// modelada sobre o health model de apps de workspace: Threshold falhas
+// consecutivas de Check transicionam o estado de Healthy para Unhealthy.
+type HealthCheck struct {
+	Name      string
+	Interval  time.Duration
+	Threshold int
+	Check     func(ctx context.Context) error
+}
+
+// probeStatus acompanha o estado corrente de uma HealthCheck registrada.
+type probeStatus struct {
+	state            ProbeState
+	consecutiveFails int
+	lastError        string
+}
+
+// HealthCheckRegistry executa um conjunto de HealthCheck em paralelo, cada
+// uma em sua própria goroutine, e agrega seus estados através de Aggregate.
+type HealthCheckRegistry struct {
+	mu     sync.RWMutex
+	probes map[string]*HealthCheck
+	status map[string]*probeStatus
+}
+
+// NewHealthCheckRegistry cria um HealthCheckRegistry vazio.
+func NewHealthCheckRegistry() *HealthCheckRegistry {
+	return &HealthCheckRegistry{
+		probes: make(map[string]*HealthCheck),
+		status: make(map[string]*probeStatus),
+	}
+}
+
+// RegisterProbe adiciona uma HealthCheck ao registry, em estado
+// ProbeInitializing, e inicia sua goroutine de execução periódica. ctx
+// cancelado encerra a goroutine.
+func (r *HealthCheckRegistry) RegisterProbe(ctx context.Context, probe *HealthCheck) {
+	r.mu.Lock()
+	r.probes[probe.Name] = probe
+	r.status[probe.Name] = &probeStatus{state: ProbeInitializing}
+	r.mu.Unlock()
+
+	go r.runProbe(ctx, probe)
+}
+
+// runProbe executa probe.Check a cada probe.Interval até ctx ser cancelado.
+func (r *HealthCheckRegistry) runProbe(ctx context.Context, probe *HealthCheck) {
+	ticker := time.NewTicker(probe.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.evaluate(probe)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// evaluate roda probe.Check uma vez e atualiza a máquina de estados da sonda:
+// Threshold falhas consecutivas levam a ProbeUnhealthy; um sucesso a
+// qualquer momento volta direto para ProbeHealthy.
+func (r *HealthCheckRegistry) evaluate(probe *HealthCheck) {
+	err := probe.Check(context.Background())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	st := r.status[probe.Name]
+	if st == nil {
+		return
+	}
+
+	if err != nil {
+		st.consecutiveFails++
+		st.lastError = err.Error()
+		if st.consecutiveFails >= probe.Threshold && st.state != ProbeUnhealthy {
+			log.Printf("⚠️ sonda %s ficou unhealthy após %d falhas consecutivas: %v", probe.Name, st.consecutiveFails, err)
+			st.state = ProbeUnhealthy
+		}
+		return
+	}
+
+	st.consecutiveFails = 0
+	st.lastError = ""
+	st.state = ProbeHealthy
+}
+
+// Status retorna o estado corrente da sonda probeName. O segundo retorno é
+// false se nenhuma sonda com esse nome estiver registrada.
+func (r *HealthCheckRegistry) Status(probeName string) (ProbeState, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	st, ok := r.status[probeName]
+	if !ok {
+		return "", false
+	}
+	return st.state, true
+}
+
+// Snapshot retorna uma cópia do estado de todas as sondas registradas,
+// usada para publicar o detalhe por sonda em AgentHealth.Probes.
+func (r *HealthCheckRegistry) Snapshot() map[string]ProbeState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := make(map[string]ProbeState, len(r.status))
+	for name, st := range r.status {
+		snapshot[name] = st.state
+	}
+	return snapshot
+}
+
+// Aggregate retorna o pior estado entre todas as sondas registradas:
+// ProbeUnhealthy domina ProbeInitializing, que domina ProbeHealthy. Um
+// registry sem sondas é considerado ProbeHealthy.
+func (r *HealthCheckRegistry) Aggregate() ProbeState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	worst := ProbeHealthy
+	for _, st := range r.status {
+		switch st.state {
+		case ProbeUnhealthy:
+			return ProbeUnhealthy
+		case ProbeInitializing:
+			worst = ProbeInitializing
+		}
+	}
+	return worst
+}
+
+// NewGroqReachabilityProbe constrói a sonda embutida que verifica se a API
+// da Groq está acessível, através de uma requisição leve ao endpoint de
+// listagem de modelos.
+func NewGroqReachabilityProbe(interval time.Duration, threshold int) *HealthCheck {
+	return &HealthCheck{
+		Name:      "groq_api",
+		Interval:  interval,
+		Threshold: threshold,
+		Check: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.groq.com/openai/v1/models", nil)
+			if err != nil {
+				return fmt.Errorf("erro ao montar requisição de health-check da Groq: %v", err)
+			}
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("GROQ_API_KEY"))
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return fmt.Errorf("erro ao contatar a API da Groq: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode >= 500 {
+				return fmt.Errorf("API da Groq retornou status %d", resp.StatusCode)
+			}
+			return nil
+		},
+	}
+}
+
+// NewRabbitMQLivenessProbe constrói a sonda embutida que verifica se o canal
+// AMQP do TaskManager informado continua aberto.
+func NewRabbitMQLivenessProbe(tm *TaskManager, interval time.Duration, threshold int) *HealthCheck {
+	return &HealthCheck{
+		Name:      "rabbitmq_channel",
+		Interval:  interval,
+		Threshold: threshold,
+		Check: func(ctx context.Context) error {
+			if !tm.IsChannelAlive() {
+				return fmt.Errorf("canal AMQP do TaskManager está fechado")
+			}
+			return nil
+		},
+	}
+}
@@ -1,12 +1,11 @@
 package agents
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
-	"net/http"
-	"os"
+
+	"HiveMindForge/agents/llm"
 )
 
 // HiveMind gerencia um conjunto de agentes e tarefas
@@ -16,27 +15,6 @@ type HiveMind struct {
 	Verbose bool
 }
 
-// GroqRequest representa a estrutura da requisição para a API da Groq
-type GroqRequest struct {
-	Model    string    `json:"model"`
-	Messages []Message `json:"messages"`
-}
-
-// Message representa uma mensagem no formato da API da Groq
-type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-// GroqResponse representa a resposta da API da Groq
-type GroqResponse struct {
-	Choices []struct {
-		Message struct {
-			Content string `json:"content"`
-		} `json:"message"`
-	} `json:"choices"`
-}
-
 // NewHiveMind cria uma nova instância do HiveMind
 func NewHiveMind(verbose bool) *HiveMind {
 	return &HiveMind{
@@ -76,10 +54,18 @@ func (h *HiveMind) Execute() (string, error) {
 		log.Printf("🤖 Agente %s executando tarefa: %s", task.Agent.Name, task.Description)
 	}
 
-	// Preparar a requisição para a Groq
-	groqReq := GroqRequest{
-		Model: "llama-3.3-70b-versatile",
-		Messages: []Message{
+	// Selecionar o Provider a partir do esquema de URL em task.Agent.Model
+	// (ex.: "groq://...", "openai://...", "ollama://host:porta/modelo"),
+	// caindo de volta para Groq quando Model não tem esquema algum; um
+	// esquema desconhecido vira erro.
+	provider, model, err := llm.ProviderForModel(task.Agent.Model)
+	if err != nil {
+		return "", fmt.Errorf("erro ao resolver provedor de LLM: %v", err)
+	}
+
+	chatReq := llm.ChatRequest{
+		Model: model,
+		Messages: []llm.ChatMessage{
 			{
 				Role: "system",
 				Content: fmt.Sprintf("Você é %s. Seu papel é %s. Seu objetivo é %s. Backstory: %s",
@@ -93,41 +79,22 @@ func (h *HiveMind) Execute() (string, error) {
 				Content: task.Description,
 			},
 		},
+		Temperature:   task.Agent.Temperature,
+		MaxTokens:     task.Agent.MaxTokens,
+		ContextWindow: task.Agent.ContextWindow,
 	}
 
-	// Converter para JSON
-	jsonData, err := json.Marshal(groqReq)
+	chatResp, err := provider.Complete(context.Background(), chatReq)
 	if err != nil {
-		return "", fmt.Errorf("erro ao criar JSON: %v", err)
+		return "", fmt.Errorf("erro ao completar tarefa via provedor de LLM: %v", err)
 	}
 
-	// Fazer a requisição para a Groq
-	req, err := http.NewRequest("POST", "https://api.groq.com/openai/v1/chat/completions", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("erro ao criar requisição: %v", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("GROQ_API_KEY"))
+	result := chatResp.Content
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("erro ao fazer requisição: %v", err)
+	if task.Agent.PerformanceStats == nil {
+		task.Agent.PerformanceStats = make(map[string]float64)
 	}
-	defer resp.Body.Close()
-
-	// Decodificar a resposta
-	var groqResp GroqResponse
-	if err := json.NewDecoder(resp.Body).Decode(&groqResp); err != nil {
-		return "", fmt.Errorf("erro ao decodificar resposta: %v", err)
-	}
-
-	if len(groqResp.Choices) == 0 {
-		return "", fmt.Errorf("nenhuma resposta recebida da API")
-	}
-
-	result := groqResp.Choices[0].Message.Content
+	task.Agent.PerformanceStats["token_usage"] = float64(chatResp.TotalTokens)
 
 	if h.Verbose {
 		log.Printf("✅ Tarefa concluída. Resultado: %s", result)
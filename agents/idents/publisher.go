@@ -0,0 +1,46 @@
+package idents
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Publish grava um único heartbeat no Redis com TTL igual a 3x o intervalo
+// de heartbeat, conforme o refactor de host-heartbeat do Nightingale.
+func Publish(ctx context.Context, redisClient *redis.Client, hb *Heartbeat, interval time.Duration) error {
+	hb.LastSeen = time.Now()
+
+	data, err := json.Marshal(hb)
+	if err != nil {
+		return err
+	}
+
+	return redisClient.Set(ctx, KeyFor(hb.AgentID), data, 3*interval).Err()
+}
+
+// StartPublishing dispara uma goroutine que chama collect a cada intervalo
+// para montar um Heartbeat atualizado e publicá-lo no Redis, até que stopChan
+// seja fechado.
+func StartPublishing(ctx context.Context, redisClient *redis.Client, interval time.Duration, stopChan <-chan struct{}, collect func() *Heartbeat) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := Publish(ctx, redisClient, collect(), interval); err != nil {
+					log.Printf("⚠️ erro ao publicar heartbeat: %v", err)
+				}
+			case <-stopChan:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
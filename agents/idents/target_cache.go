@@ -0,0 +1,171 @@
+package idents
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// EventType identifica se um evento de TargetCache é uma entrada ou saída de agente.
+type EventType int
+
+const (
+	EventJoin EventType = iota
+	EventLeave
+)
+
+// Event é emitido sempre que um agente entra ou sai do snapshot em memória.
+type Event struct {
+	Type      EventType
+	Heartbeat *Heartbeat
+}
+
+// TargetCache mantém um snapshot em memória dos heartbeats publicados no Redis,
+// atualizado por uma goroutine de fundo que varre o keyspace `ident:*`.
+//
+// Modelado a partir do memsto/target_cache.go do Nightingale: o orquestrador
+// consulta apenas o snapshot local, nunca o Redis diretamente, o que permite
+// gerenciar agentes rodando em outros processos/pods sem acoplar cada chamada
+// a uma ida à rede.
+type TargetCache struct {
+	redisClient *redis.Client
+	interval    time.Duration
+
+	mu       sync.RWMutex
+	targets  map[string]*Heartbeat
+	watchers []chan Event
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewTargetCache cria um TargetCache que varre o Redis no intervalo informado.
+func NewTargetCache(redisClient *redis.Client, refreshInterval time.Duration) *TargetCache {
+	return &TargetCache{
+		redisClient: redisClient,
+		interval:    refreshInterval,
+		targets:     make(map[string]*Heartbeat),
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start inicia a goroutine de fundo que mantém o snapshot atualizado.
+func (c *TargetCache) Start(ctx context.Context) {
+	go c.run(ctx)
+}
+
+// Stop encerra a goroutine de atualização.
+func (c *TargetCache) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stopChan)
+	})
+}
+
+func (c *TargetCache) run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.refresh(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			c.refresh(ctx)
+		case <-c.stopChan:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refresh varre o keyspace `ident:*` no Redis e substitui o snapshot em
+// memória, emitindo eventos de join/leave para quem estiver observando.
+func (c *TargetCache) refresh(ctx context.Context) {
+	fresh := make(map[string]*Heartbeat)
+
+	iter := c.redisClient.Scan(ctx, 0, "ident:*", 0).Iterator()
+	for iter.Next(ctx) {
+		data, err := c.redisClient.Get(ctx, iter.Val()).Bytes()
+		if err != nil {
+			continue
+		}
+
+		var hb Heartbeat
+		if err := json.Unmarshal(data, &hb); err != nil {
+			log.Printf("erro ao decodificar heartbeat de %s: %v", iter.Val(), err)
+			continue
+		}
+
+		fresh[hb.AgentID] = &hb
+	}
+	if err := iter.Err(); err != nil {
+		log.Printf("erro ao varrer keyspace de idents: %v", err)
+		return
+	}
+
+	c.mu.Lock()
+	previous := c.targets
+	c.targets = fresh
+	c.mu.Unlock()
+
+	for agentID, hb := range fresh {
+		if _, existed := previous[agentID]; !existed {
+			c.notify(Event{Type: EventJoin, Heartbeat: hb})
+		}
+	}
+	for agentID, hb := range previous {
+		if _, stillThere := fresh[agentID]; !stillThere {
+			c.notify(Event{Type: EventLeave, Heartbeat: hb})
+		}
+	}
+}
+
+func (c *TargetCache) notify(ev Event) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, ch := range c.watchers {
+		select {
+		case ch <- ev:
+		default:
+			log.Printf("⚠️ watcher de TargetCache com canal cheio, evento descartado")
+		}
+	}
+}
+
+// Watch registra um canal que recebe eventos de join/leave. O canal é criado
+// aqui com buffer de 16 eventos; eventos são descartados se o buffer encher.
+func (c *TargetCache) Watch() <-chan Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan Event, 16)
+	c.watchers = append(c.watchers, ch)
+	return ch
+}
+
+// Get retorna o heartbeat mais recente conhecido para um agente.
+func (c *TargetCache) Get(agentID string) (*Heartbeat, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	hb, ok := c.targets[agentID]
+	return hb, ok
+}
+
+// All retorna o snapshot atual de todos os agentes vivos.
+func (c *TargetCache) All() []*Heartbeat {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make([]*Heartbeat, 0, len(c.targets))
+	for _, hb := range c.targets {
+		out = append(out, hb)
+	}
+	return out
+}
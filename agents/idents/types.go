@@ -0,0 +1,26 @@
+package idents
+
+import "time"
+
+// Heartbeat representa o documento de identidade que cada CognitiveAgent
+// publica periodicamente no Redis para se anunciar ao orquestrador.
+type Heartbeat struct {
+	AgentID       string    `json:"agent_id"`
+	Role          string    `json:"role"`
+	Version       string    `json:"version"`
+	CPUUsage      float64   `json:"cpu_usage"`
+	MemoryUsage   uint64    `json:"memory_usage"`
+	InFlightTasks int       `json:"in_flight_tasks"`
+	ErrorCount    int       `json:"error_count"`
+	LastSeen      time.Time `json:"last_seen"`
+
+	// Attributes rotula este host para decisões de placement (ex.: "region",
+	// "gpu", "datacenter"), consultadas pelo scheduler do orquestrador ao
+	// escolher onde colocar uma nova instância.
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// KeyFor retorna a chave Redis usada para armazenar o heartbeat de um agente.
+func KeyFor(agentID string) string {
+	return "ident:" + agentID
+}
@@ -0,0 +1,200 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const anthropicVersion = "2023-06-01"
+
+// AnthropicProvider fala com a Messages API da Anthropic
+// (api.anthropic.com/v1/messages), cujo formato separa a mensagem de
+// sistema das demais e representa o conteúdo da resposta como uma lista de
+// blocos, em vez do formato choices[].message da OpenAI.
+type AnthropicProvider struct {
+	apiKey string
+}
+
+// NewAnthropicProvider cria um AnthropicProvider autenticado com a variável
+// de ambiente ANTHROPIC_API_KEY.
+func NewAnthropicProvider() *AnthropicProvider {
+	return &AnthropicProvider{apiKey: os.Getenv("ANTHROPIC_API_KEY")}
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Temperature float64            `json:"temperature,omitempty"`
+	MaxTokens   int                `json:"max_tokens"`
+	Stream      bool               `json:"stream,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+}
+
+// splitAnthropicMessages separa a primeira mensagem "system" (se houver) do
+// restante da conversa, já que a Messages API da Anthropic não aceita
+// mensagens de sistema na lista messages.
+func splitAnthropicMessages(messages []ChatMessage) (system string, rest []anthropicMessage) {
+	for _, m := range messages {
+		if m.Role == "system" && system == "" {
+			system = m.Content
+			continue
+		}
+		rest = append(rest, anthropicMessage{Role: m.Role, Content: m.Content})
+	}
+	return system, rest
+}
+
+func (p *AnthropicProvider) newRequest(ctx context.Context, body anthropicRequest) (*http.Request, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar JSON: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	return req, nil
+}
+
+func (p *AnthropicProvider) Complete(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	system, messages := splitAnthropicMessages(req.Messages)
+
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 1024
+	}
+
+	httpReq, err := p.newRequest(ctx, anthropicRequest{
+		Model:       req.Model,
+		System:      system,
+		Messages:    messages,
+		Temperature: req.Temperature,
+		MaxTokens:   maxTokens,
+	})
+	if err != nil {
+		return ChatResponse{}, err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("erro ao fazer requisição: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var anthResp anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&anthResp); err != nil {
+		return ChatResponse{}, fmt.Errorf("erro ao decodificar resposta: %v", err)
+	}
+
+	if len(anthResp.Content) == 0 {
+		return ChatResponse{}, fmt.Errorf("nenhuma resposta recebida da API")
+	}
+
+	var content strings.Builder
+	for _, block := range anthResp.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+
+	return ChatResponse{
+		Content:          content.String(),
+		PromptTokens:     anthResp.Usage.InputTokens,
+		CompletionTokens: anthResp.Usage.OutputTokens,
+		TotalTokens:      anthResp.Usage.InputTokens + anthResp.Usage.OutputTokens,
+	}, nil
+}
+
+func (p *AnthropicProvider) Stream(ctx context.Context, req ChatRequest, onChunk func(StreamChunk) error) error {
+	system, messages := splitAnthropicMessages(req.Messages)
+
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 1024
+	}
+
+	httpReq, err := p.newRequest(ctx, anthropicRequest{
+		Model:       req.Model,
+		System:      system,
+		Messages:    messages,
+		Temperature: req.Temperature,
+		MaxTokens:   maxTokens,
+		Stream:      true,
+	})
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("erro ao fazer requisição: %v", err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			continue
+		}
+
+		switch event.Type {
+		case "content_block_delta":
+			if err := onChunk(StreamChunk{Content: event.Delta.Text}); err != nil {
+				return err
+			}
+		case "message_stop":
+			return onChunk(StreamChunk{Done: true})
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (p *AnthropicProvider) Embed(ctx context.Context, req EmbedRequest) (EmbedResponse, error) {
+	return EmbedResponse{}, fmt.Errorf("anthropic: embeddings não são suportados por este provedor")
+}
@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// GroqProvider fala com a API de chat completions da Groq
+// (api.groq.com/openai/v1), que segue o mesmo dialeto da OpenAI.
+type GroqProvider struct {
+	client *openAICompatibleClient
+}
+
+// NewGroqProvider cria um GroqProvider autenticado com a variável de
+// ambiente GROQ_API_KEY, mantendo o mesmo contrato usado antes desta camada
+// existir em HiveMind.Execute.
+func NewGroqProvider() *GroqProvider {
+	return &GroqProvider{
+		client: &openAICompatibleClient{
+			baseURL: "https://api.groq.com/openai/v1/chat/completions",
+			authHeader: func(apiKey string) (string, string) {
+				return "Authorization", "Bearer " + apiKey
+			},
+			apiKey: os.Getenv("GROQ_API_KEY"),
+		},
+	}
+}
+
+func (p *GroqProvider) Complete(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return p.client.complete(ctx, req)
+}
+
+func (p *GroqProvider) Stream(ctx context.Context, req ChatRequest, onChunk func(StreamChunk) error) error {
+	return p.client.stream(ctx, req, onChunk)
+}
+
+func (p *GroqProvider) Embed(ctx context.Context, req EmbedRequest) (EmbedResponse, error) {
+	return EmbedResponse{}, fmt.Errorf("groq: embeddings não são suportados por este provedor")
+}
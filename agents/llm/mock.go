@@ -0,0 +1,61 @@
+package llm
+
+import (
+	"context"
+	"strings"
+)
+
+// MockProvider é um Provider local, sem acesso a rede, selecionado via
+// "mock://" em CognitiveAgent.Model. Útil em desenvolvimento e testes: ecoa
+// a última mensagem do usuário prefixada pelo nome do modelo, com uma
+// contagem de tokens aproximada (uma por palavra).
+type MockProvider struct{}
+
+// NewMockProvider cria um MockProvider.
+func NewMockProvider() *MockProvider {
+	return &MockProvider{}
+}
+
+func lastUserMessage(messages []ChatMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
+
+func (p *MockProvider) Complete(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	content := "[mock:" + req.Model + "] " + lastUserMessage(req.Messages)
+	tokens := len(strings.Fields(content))
+
+	return ChatResponse{
+		Content:          content,
+		PromptTokens:     len(strings.Fields(lastUserMessage(req.Messages))),
+		CompletionTokens: tokens,
+		TotalTokens:      tokens,
+	}, nil
+}
+
+func (p *MockProvider) Stream(ctx context.Context, req ChatRequest, onChunk func(StreamChunk) error) error {
+	resp, err := p.Complete(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for _, word := range strings.Fields(resp.Content) {
+		if err := onChunk(StreamChunk{Content: word + " "}); err != nil {
+			return err
+		}
+	}
+
+	return onChunk(StreamChunk{Done: true})
+}
+
+func (p *MockProvider) Embed(ctx context.Context, req EmbedRequest) (EmbedResponse, error) {
+	vector := make([]float64, 8)
+	for i, r := range req.Input {
+		vector[i%len(vector)] += float64(r)
+	}
+	return EmbedResponse{Vector: vector}, nil
+}
@@ -0,0 +1,177 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// OllamaProvider fala com um servidor Ollama local (API /api/chat e
+// /api/embeddings), identificado por host:porta extraído do esquema
+// "ollama://host:porta/modelo" em CognitiveAgent.Model.
+type OllamaProvider struct {
+	addr string // host:porta, ex.: "localhost:11434"
+}
+
+// NewOllamaProviderFromAddr separa "host:porta/modelo" (a parte após
+// "ollama://") em addr (host:porta) e model (o que sobra do caminho), já
+// que o Model do agente carrega os dois juntos.
+func NewOllamaProviderFromAddr(hostAndModel string) (Provider, string, error) {
+	addr, model, ok := strings.Cut(hostAndModel, "/")
+	if !ok || addr == "" {
+		return nil, "", fmt.Errorf("ollama: esperado \"host:porta/modelo\" em Model, recebido %q", hostAndModel)
+	}
+	return &OllamaProvider{addr: addr}, model, nil
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Options  ollamaOptions   `json:"options,omitempty"`
+}
+
+type ollamaOptions struct {
+	Temperature float64 `json:"temperature,omitempty"`
+	NumCtx      int     `json:"num_ctx,omitempty"`
+	NumPredict  int     `json:"num_predict,omitempty"`
+}
+
+type ollamaChatResponse struct {
+	Message        ollamaMessage `json:"message"`
+	Done           bool          `json:"done"`
+	PromptEvalCont int           `json:"prompt_eval_count"`
+	EvalCount      int           `json:"eval_count"`
+}
+
+func (p *OllamaProvider) requestBody(req ChatRequest, stream bool) ollamaChatRequest {
+	messages := make([]ollamaMessage, len(req.Messages))
+	for i, m := range req.Messages {
+		messages[i] = ollamaMessage{Role: m.Role, Content: m.Content}
+	}
+
+	return ollamaChatRequest{
+		Model:    req.Model,
+		Messages: messages,
+		Stream:   stream,
+		Options: ollamaOptions{
+			Temperature: req.Temperature,
+			NumCtx:      req.ContextWindow,
+			NumPredict:  req.MaxTokens,
+		},
+	}
+}
+
+func (p *OllamaProvider) newRequest(ctx context.Context, path string, body interface{}) (*http.Request, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar JSON: %v", err)
+	}
+
+	url := fmt.Sprintf("http://%s%s", p.addr, path)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+func (p *OllamaProvider) Complete(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	httpReq, err := p.newRequest(ctx, "/api/chat", p.requestBody(req, false))
+	if err != nil {
+		return ChatResponse{}, err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("erro ao fazer requisição: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var chatResp ollamaChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return ChatResponse{}, fmt.Errorf("erro ao decodificar resposta: %v", err)
+	}
+
+	return ChatResponse{
+		Content:          chatResp.Message.Content,
+		PromptTokens:     chatResp.PromptEvalCont,
+		CompletionTokens: chatResp.EvalCount,
+		TotalTokens:      chatResp.PromptEvalCont + chatResp.EvalCount,
+	}, nil
+}
+
+func (p *OllamaProvider) Stream(ctx context.Context, req ChatRequest, onChunk func(StreamChunk) error) error {
+	httpReq, err := p.newRequest(ctx, "/api/chat", p.requestBody(req, true))
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("erro ao fazer requisição: %v", err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var chunk ollamaChatResponse
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			continue
+		}
+
+		if err := onChunk(StreamChunk{Content: chunk.Message.Content, Done: chunk.Done}); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+type ollamaEmbedRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+type ollamaEmbedResponse struct {
+	Embedding []float64 `json:"embedding"`
+}
+
+func (p *OllamaProvider) Embed(ctx context.Context, req EmbedRequest) (EmbedResponse, error) {
+	httpReq, err := p.newRequest(ctx, "/api/embeddings", ollamaEmbedRequest{Model: req.Model, Prompt: req.Input})
+	if err != nil {
+		return EmbedResponse{}, err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return EmbedResponse{}, fmt.Errorf("erro ao fazer requisição: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var embedResp ollamaEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return EmbedResponse{}, fmt.Errorf("erro ao decodificar resposta: %v", err)
+	}
+
+	return EmbedResponse{Vector: embedResp.Embedding}, nil
+}
@@ -0,0 +1,84 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// OpenAIProvider fala com a API de chat completions e embeddings da OpenAI
+// (api.openai.com/v1).
+type OpenAIProvider struct {
+	client *openAICompatibleClient
+	apiKey string
+}
+
+// NewOpenAIProvider cria um OpenAIProvider autenticado com a variável de
+// ambiente OPENAI_API_KEY.
+func NewOpenAIProvider() *OpenAIProvider {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	return &OpenAIProvider{
+		client: &openAICompatibleClient{
+			baseURL: "https://api.openai.com/v1/chat/completions",
+			authHeader: func(apiKey string) (string, string) {
+				return "Authorization", "Bearer " + apiKey
+			},
+			apiKey: apiKey,
+		},
+		apiKey: apiKey,
+	}
+}
+
+func (p *OpenAIProvider) Complete(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return p.client.complete(ctx, req)
+}
+
+func (p *OpenAIProvider) Stream(ctx context.Context, req ChatRequest, onChunk func(StreamChunk) error) error {
+	return p.client.stream(ctx, req, onChunk)
+}
+
+type openAIEmbedRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+type openAIEmbedResponse struct {
+	Data []struct {
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+}
+
+func (p *OpenAIProvider) Embed(ctx context.Context, req EmbedRequest) (EmbedResponse, error) {
+	jsonData, err := json.Marshal(openAIEmbedRequest{Model: req.Model, Input: req.Input})
+	if err != nil {
+		return EmbedResponse{}, fmt.Errorf("erro ao criar JSON: %v", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/embeddings", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return EmbedResponse{}, fmt.Errorf("erro ao criar requisição: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	client := &http.Client{}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return EmbedResponse{}, fmt.Errorf("erro ao fazer requisição: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var embedResp openAIEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return EmbedResponse{}, fmt.Errorf("erro ao decodificar resposta: %v", err)
+	}
+
+	if len(embedResp.Data) == 0 {
+		return EmbedResponse{}, fmt.Errorf("nenhum embedding recebido da API")
+	}
+
+	return EmbedResponse{Vector: embedResp.Data[0].Embedding}, nil
+}
@@ -0,0 +1,167 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// openAIChatRequest é o corpo de requisição comum às APIs de chat completion
+// compatíveis com o formato da OpenAI (Groq e OpenAI em si).
+type openAIChatRequest struct {
+	Model       string              `json:"model"`
+	Messages    []openAIChatMessage `json:"messages"`
+	Temperature float64             `json:"temperature,omitempty"`
+	MaxTokens   int                 `json:"max_tokens,omitempty"`
+	Stream      bool                `json:"stream,omitempty"`
+}
+
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIChatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// openAIChatStreamChunk é o formato de cada evento "data: {...}" do stream
+// SSE devolvido pelas APIs compatíveis com a OpenAI.
+type openAIChatStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// openAICompatibleClient encapsula a chamada HTTP comum a provedores que
+// falam o dialeto de chat completions da OpenAI, variando apenas a URL base
+// e o cabeçalho de autenticação.
+type openAICompatibleClient struct {
+	baseURL    string
+	authHeader func(apiKey string) (name, value string)
+	apiKey     string
+}
+
+func toOpenAIMessages(messages []ChatMessage) []openAIChatMessage {
+	out := make([]openAIChatMessage, len(messages))
+	for i, m := range messages {
+		out[i] = openAIChatMessage{Role: m.Role, Content: m.Content}
+	}
+	return out
+}
+
+func (c *openAICompatibleClient) newRequest(ctx context.Context, body openAIChatRequest) (*http.Request, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar JSON: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	name, value := c.authHeader(c.apiKey)
+	req.Header.Set(name, value)
+
+	return req, nil
+}
+
+func (c *openAICompatibleClient) complete(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	httpReq, err := c.newRequest(ctx, openAIChatRequest{
+		Model:       req.Model,
+		Messages:    toOpenAIMessages(req.Messages),
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+	})
+	if err != nil {
+		return ChatResponse{}, err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("erro ao fazer requisição: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var chatResp openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return ChatResponse{}, fmt.Errorf("erro ao decodificar resposta: %v", err)
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return ChatResponse{}, fmt.Errorf("nenhuma resposta recebida da API")
+	}
+
+	return ChatResponse{
+		Content:          chatResp.Choices[0].Message.Content,
+		PromptTokens:     chatResp.Usage.PromptTokens,
+		CompletionTokens: chatResp.Usage.CompletionTokens,
+		TotalTokens:      chatResp.Usage.TotalTokens,
+	}, nil
+}
+
+func (c *openAICompatibleClient) stream(ctx context.Context, req ChatRequest, onChunk func(StreamChunk) error) error {
+	httpReq, err := c.newRequest(ctx, openAIChatRequest{
+		Model:       req.Model,
+		Messages:    toOpenAIMessages(req.Messages),
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+		Stream:      true,
+	})
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("erro ao fazer requisição: %v", err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "[DONE]" {
+			return onChunk(StreamChunk{Done: true})
+		}
+
+		var chunk openAIChatStreamChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			continue
+		}
+
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		done := chunk.Choices[0].FinishReason != nil
+		if err := onChunk(StreamChunk{Content: chunk.Choices[0].Delta.Content, Done: done}); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,69 @@
+// Package llm abstrai o acesso a provedores de modelos de linguagem por trás
+// de uma única interface Provider, selecionada em tempo de execução pelo
+// esquema de URL do campo Model de um agente (ex.: "groq://llama-3.3-70b",
+// "openai://gpt-4o", "ollama://localhost:11434/llama3"). Isso permite trocar
+// de provedor por agente sem recompilar HiveMind.Execute.
+package llm
+
+import "context"
+
+// ChatMessage é uma mensagem trocada num chat completion, no formato comum
+// à maioria das APIs de chat (role "system"/"user"/"assistant" + content).
+type ChatMessage struct {
+	Role    string
+	Content string
+}
+
+// ChatRequest carrega tudo que um Provider precisa para gerar uma resposta:
+// o modelo (já sem o esquema, ex.: "llama-3.3-70b-versatile"), o histórico
+// de mensagens e os parâmetros de geração configurados no agente.
+type ChatRequest struct {
+	Model         string
+	Messages      []ChatMessage
+	Temperature   float64
+	MaxTokens     int
+	ContextWindow int
+}
+
+// ChatResponse é o resultado de um Complete bem-sucedido, incluindo a
+// contagem de tokens quando o provedor a expõe — usada para preencher
+// PerformanceStats["token_usage"] do agente que fez a chamada.
+type ChatResponse struct {
+	Content          string
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// StreamChunk é um fragmento de uma resposta em streaming. Done é true no
+// último chunk entregue, após o qual nenhum outro chunk chega.
+type StreamChunk struct {
+	Content string
+	Done    bool
+}
+
+// EmbedRequest pede o embedding de um texto a um Provider.
+type EmbedRequest struct {
+	Model string
+	Input string
+}
+
+// EmbedResponse é o vetor de embedding retornado por um Provider.
+type EmbedResponse struct {
+	Vector []float64
+}
+
+// Provider é implementado por cada backend de LLM suportado (Groq, OpenAI,
+// Anthropic, Ollama, e o Mock usado em desenvolvimento/testes sem rede).
+type Provider interface {
+	// Complete gera uma resposta completa para o ChatRequest informado.
+	Complete(ctx context.Context, req ChatRequest) (ChatResponse, error)
+
+	// Stream gera a resposta incrementalmente, chamando onChunk para cada
+	// fragmento recebido. onChunk recebendo um erro interrompe o streaming.
+	Stream(ctx context.Context, req ChatRequest, onChunk func(StreamChunk) error) error
+
+	// Embed calcula o embedding de um texto. Provedores sem suporte a
+	// embeddings retornam um erro descrevendo a limitação.
+	Embed(ctx context.Context, req EmbedRequest) (EmbedResponse, error)
+}
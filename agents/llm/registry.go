@@ -0,0 +1,47 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProviderForModel resolve, a partir do esquema de URL em CognitiveAgent.Model
+// (ex.: "groq://llama-3.3-70b-versatile", "openai://gpt-4o",
+// "anthropic://claude-3-5-sonnet-latest", "ollama://localhost:11434/llama3",
+// "mock://echo"), qual Provider usar e qual identificador de modelo repassar
+// a ele (a parte após "://"). Um Model sem "://" cai de volta para Groq com
+// o valor original, preservando o comportamento de agentes configurados
+// antes da introdução desta camada; um esquema fora da lista acima devolve
+// erro.
+func ProviderForModel(model string) (Provider, string, error) {
+	scheme, rest, ok := splitScheme(model)
+	if !ok {
+		return NewGroqProvider(), model, nil
+	}
+
+	switch scheme {
+	case "groq":
+		return NewGroqProvider(), rest, nil
+	case "openai":
+		return NewOpenAIProvider(), rest, nil
+	case "anthropic":
+		return NewAnthropicProvider(), rest, nil
+	case "ollama":
+		return NewOllamaProviderFromAddr(rest)
+	case "mock":
+		return NewMockProvider(), rest, nil
+	default:
+		return nil, "", fmt.Errorf("provedor de LLM desconhecido: %q", scheme)
+	}
+}
+
+// splitScheme separa o esquema ("groq", "ollama", ...) do restante de um
+// Model no formato "esquema://restante". ok é false quando não há "://",
+// caso em que model deve ser tratado como um nome de modelo sem esquema.
+func splitScheme(model string) (scheme, rest string, ok bool) {
+	idx := strings.Index(model, "://")
+	if idx < 0 {
+		return "", "", false
+	}
+	return model[:idx], model[idx+len("://"):], true
+}
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"os"
+)
+
+// Embedder converte texto em um vetor de embedding usado para busca
+// semântica. Ter múltiplos provedores por trás de uma única interface
+// permite trocar de um serviço remoto (OpenAI) para um modelo local (ONNX)
+// sem tocar em SearchMemoriesSemantic.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// NewEmbedder constrói o Embedder identificado por provider ("openai",
+// "local" ou "fake") para o modelo informado.
+func NewEmbedder(provider, model string) (Embedder, error) {
+	switch provider {
+	case "openai":
+		return &OpenAIEmbedder{Model: model, APIKey: os.Getenv("OPENAI_API_KEY")}, nil
+	case "local":
+		return &LocalEmbedder{Model: model}, nil
+	case "fake", "":
+		return &FakeEmbedder{}, nil
+	default:
+		return nil, fmt.Errorf("provedor de embeddings desconhecido: %s", provider)
+	}
+}
+
+// OpenAIEmbedder chama o endpoint de embeddings da OpenAI.
+type OpenAIEmbedder struct {
+	Model  string
+	APIKey string
+}
+
+type openAIEmbeddingRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed chama a API de embeddings da OpenAI e retorna o vetor resultante.
+func (e *OpenAIEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	body, err := json.Marshal(openAIEmbeddingRequest{Model: e.Model, Input: text})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao montar requisição de embedding: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição de embedding: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.APIKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao chamar API de embeddings: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var parsed openAIEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta de embedding: %v", err)
+	}
+	if len(parsed.Data) == 0 {
+		return nil, fmt.Errorf("nenhum embedding retornado pela API")
+	}
+
+	return parsed.Data[0].Embedding, nil
+}
+
+// LocalEmbedder é um placeholder para um modelo ONNX rodando localmente.
+// A integração real com um runtime ONNX fica fora do escopo deste pacote;
+// por ora cai para o mesmo hashing determinístico do FakeEmbedder.
+type LocalEmbedder struct {
+	Model string
+}
+
+// Embed gera um vetor determinístico a partir do texto via hashing, até que
+// um runtime ONNX real seja plugado.
+func (e *LocalEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return hashEmbedding(text, 32), nil
+}
+
+// FakeEmbedder gera embeddings determinísticos a partir de um hash do texto,
+// úteis em testes e ambientes sem acesso a um provedor real.
+type FakeEmbedder struct{}
+
+// Embed retorna um vetor determinístico derivado do texto.
+func (e *FakeEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return hashEmbedding(text, 16), nil
+}
+
+// hashEmbedding deriva um vetor pseudo-aleatório, porém determinístico, a
+// partir do hash FNV do texto — suficiente para exercitar o caminho de busca
+// semântica sem depender de um modelo real.
+func hashEmbedding(text string, dims int) []float32 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(text))
+	src := rand.New(rand.NewSource(int64(h.Sum64())))
+
+	vec := make([]float32, dims)
+	for i := range vec {
+		vec[i] = src.Float32()*2 - 1
+	}
+	return vec
+}
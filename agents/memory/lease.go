@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MemoryRef é uma referência com contagem de leases a uma Memory, no
+// espírito de Ref/ImmutableRef do BuildKit (cache/refs.go): enquanto houver
+// uma lease viva sobre o ID da memória, ConsolidateMemories e PruneMemories
+// recusam-se a removê-la ou migrá-la.
+type MemoryRef struct {
+	*Memory
+
+	leaseID string
+	manager *HybridMemoryManager
+}
+
+// Release decrementa a lease desta referência, liberando a memória para
+// consolidação/poda caso não haja outras leases vivas.
+func (r *MemoryRef) Release(ctx context.Context) error {
+	if r.manager == nil {
+		return nil
+	}
+	return r.manager.releaseLease(ctx, r.Memory.ID, r.leaseID)
+}
+
+func leaseKeyPrefix(memoryID string) string {
+	return fmt.Sprintf("lease:%s:", memoryID)
+}
+
+func leaseKey(memoryID, leaseID string) string {
+	return leaseKeyPrefix(memoryID) + leaseID
+}
+
+// acquireLease registra uma nova lease para memoryID em nome de ownerID
+// (o agente que está lendo a memória) e retorna seu ID.
+func (m *HybridMemoryManager) acquireLease(ctx context.Context, ownerID, memoryID string) (string, error) {
+	leaseID := fmt.Sprintf("%s-%d", ownerID, time.Now().UnixNano())
+
+	ttl := m.config.LeaseTTL
+	if ttl <= 0 {
+		ttl = 60 * time.Second
+	}
+
+	if err := m.shortStore.Set(ctx, leaseKey(memoryID, leaseID), []byte(ownerID), ttl); err != nil {
+		return "", fmt.Errorf("erro ao registrar lease: %v", err)
+	}
+
+	return leaseID, nil
+}
+
+// releaseLease remove uma lease específica.
+func (m *HybridMemoryManager) releaseLease(ctx context.Context, memoryID, leaseID string) error {
+	return m.shortStore.Delete(ctx, leaseKey(memoryID, leaseID))
+}
+
+// hasLiveLease indica se existe ao menos uma lease viva sobre a memória.
+func (m *HybridMemoryManager) hasLiveLease(ctx context.Context, memoryID string) bool {
+	keys, err := m.shortStore.Scan(ctx, leaseKeyPrefix(memoryID)+"*")
+	if err != nil {
+		return false
+	}
+	return len(keys) > 0
+}
+
+// newRef empacota uma Memory numa MemoryRef com uma lease recém-adquirida.
+func (m *HybridMemoryManager) newRef(ctx context.Context, ownerID string, mem *Memory) (*MemoryRef, error) {
+	mem.manager = m
+
+	leaseID, err := m.acquireLease(ctx, ownerID, mem.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MemoryRef{Memory: mem, leaseID: leaseID, manager: m}, nil
+}
+
+// finalize comita uma memória de curto prazo para o armazenamento de longo
+// prazo, usado por Memory.Finalize.
+func (m *HybridMemoryManager) finalize(ctx context.Context, mem *Memory) error {
+	if mem.Type == LongTerm {
+		return nil
+	}
+
+	mem.Type = LongTerm
+	if err := m.StoreMemory(ctx, mem); err != nil {
+		return fmt.Errorf("erro ao finalizar memória: %v", err)
+	}
+
+	return m.shortStore.Delete(ctx, keyFor(mem.AgentID, mem.ID))
+}
+
+// ReapStaleLeases varre todas as leases registradas e remove aquelas cujo
+// dono, segundo isAgentAlive (tipicamente apoiado no TargetCache de
+// heartbeats), não está mais vivo — evitando que um agente que tenha
+// crashado prenda memórias para sempre.
+func (m *HybridMemoryManager) ReapStaleLeases(ctx context.Context, isAgentAlive func(agentID string) bool) error {
+	keys, err := m.shortStore.Scan(ctx, "lease:*")
+	if err != nil {
+		return fmt.Errorf("erro ao varrer leases: %v", err)
+	}
+
+	for _, key := range keys {
+		ownerID, err := m.shortStore.Get(ctx, key)
+		if err != nil {
+			continue
+		}
+
+		if isAgentAlive(string(ownerID)) {
+			continue
+		}
+
+		if err := m.shortStore.Delete(ctx, key); err != nil {
+			return fmt.Errorf("erro ao reapar lease %s: %v", key, err)
+		}
+	}
+
+	return nil
+}
+
+// StartLeaseGC dispara uma goroutine de fundo que chama ReapStaleLeases
+// periodicamente, até que ctx seja cancelado.
+func StartLeaseGC(ctx context.Context, m *HybridMemoryManager, interval time.Duration, isAgentAlive func(agentID string) bool) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				_ = m.ReapStaleLeases(ctx, isAgentAlive)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
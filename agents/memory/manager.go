@@ -4,218 +4,362 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"time"
-
-	"github.com/go-redis/redis/v8"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// HybridMemoryManager implementa a interface MemoryManager usando Redis e MongoDB
+// HybridMemoryManager implementa MemoryManager compondo duas camadas de
+// MemoryStore plugáveis — uma para curto prazo e outra para longo prazo,
+// escolhidas via MemoryConfig.ShortTermBackend/LongTermBackend — em vez de
+// depender diretamente de um cliente Redis e Mongo concretos.
 type HybridMemoryManager struct {
-	config      *MemoryConfig
-	redisClient *redis.Client
-	mongoClient *mongo.Client
-	collection  *mongo.Collection
+	config     *MemoryConfig
+	shortStore MemoryStore
+	longStore  MemoryStore
+
+	// sharder e localID, quando configurados, particionam o Scan em
+	// SearchMemories: cada réplica só considera as chaves das quais é dona.
+	sharder Sharder
+	localID string
+
+	// embedder e vectorIndex habilitam SearchMemoriesSemantic. vectorIndex é
+	// nil quando VectorIndexType aponta para o MongoDB Atlas, já que nesse
+	// caso a busca é delegada ao próprio longStore via $vectorSearch.
+	embedder    Embedder
+	vectorIndex VectorIndex
 }
 
-// NewHybridMemoryManager cria uma nova instância do gerenciador de memória híbrido
+// NewHybridMemoryManager cria um gerenciador de memória compondo o
+// MemoryStore de curto prazo e o de longo prazo definidos em config.
 func NewHybridMemoryManager(ctx context.Context, config *MemoryConfig) (*HybridMemoryManager, error) {
-	// Conecta ao Redis
-	opt, err := redis.ParseURL(config.RedisURL)
+	shortStore, err := NewMemoryStore(ctx, config.ShortTermBackend, config)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao parsear URL do Redis: %v", err)
+		return nil, fmt.Errorf("erro ao criar store de curto prazo (%s): %v", config.ShortTermBackend, err)
 	}
-	redisClient := redis.NewClient(opt)
 
-	// Testa conexão com Redis
-	if err := redisClient.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("erro ao conectar ao Redis: %v", err)
+	longStore, err := NewMemoryStore(ctx, config.LongTermBackend, config)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar store de longo prazo (%s): %v", config.LongTermBackend, err)
 	}
 
-	// Conecta ao MongoDB
-	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(config.MongoURL))
+	embedder, err := NewEmbedder(config.EmbedderProvider, config.EmbedderModel)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao conectar ao MongoDB: %v", err)
-	}
-
-	// Testa conexão com MongoDB
-	if err := mongoClient.Ping(ctx, nil); err != nil {
-		return nil, fmt.Errorf("erro ao conectar ao MongoDB: %v", err)
-	}
-
-	collection := mongoClient.Database(config.MongoDB).Collection(config.Collection)
-
-	// Cria índices no MongoDB
-	indexes := []mongo.IndexModel{
-		{
-			Keys: bson.D{
-				{Key: "agent_id", Value: 1},
-				{Key: "type", Value: 1},
-			},
-		},
-		{
-			Keys: bson.D{
-				{Key: "tags", Value: 1},
-			},
-		},
-		{
-			Keys: bson.D{
-				{Key: "created_at", Value: 1},
-			},
-		},
-	}
-
-	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
-		return nil, fmt.Errorf("erro ao criar índices no MongoDB: %v", err)
-	}
-
-	return &HybridMemoryManager{
-		config:      config,
-		redisClient: redisClient,
-		mongoClient: mongoClient,
-		collection:  collection,
-	}, nil
+		return nil, fmt.Errorf("erro ao criar embedder (%s): %v", config.EmbedderProvider, err)
+	}
+
+	m := &HybridMemoryManager{
+		config:     config,
+		shortStore: shortStore,
+		longStore:  longStore,
+		embedder:   embedder,
+	}
+
+	if config.VectorIndexType != "mongo_atlas" {
+		m.vectorIndex = NewFlatIndex()
+		if err := m.BuildIndex(ctx); err != nil {
+			return nil, fmt.Errorf("erro ao construir índice vetorial: %v", err)
+		}
+	}
+
+	return m, nil
 }
 
-// Close fecha as conexões com Redis e MongoDB
-func (m *HybridMemoryManager) Close(ctx context.Context) error {
-	if err := m.redisClient.Close(); err != nil {
-		return fmt.Errorf("erro ao fechar conexão com Redis: %v", err)
+// BuildIndex (re)constrói o índice vetorial em memória varrendo todas as
+// memórias já armazenadas e reembutindo seus conteúdos, usado na inicialização
+// quando VectorIndexType for "flat".
+func (m *HybridMemoryManager) BuildIndex(ctx context.Context) error {
+	if m.vectorIndex == nil {
+		return nil
+	}
+
+	for _, store := range []MemoryStore{m.shortStore, m.longStore} {
+		keys, err := store.Scan(ctx, "memory:*")
+		if err != nil {
+			return fmt.Errorf("erro ao varrer memórias para o índice vetorial: %v", err)
+		}
+
+		for _, key := range keys {
+			data, err := store.Get(ctx, key)
+			if err != nil {
+				continue
+			}
+
+			var mem Memory
+			if err := json.Unmarshal(data, &mem); err != nil {
+				continue
+			}
+
+			if len(mem.Embedding) > 0 {
+				m.vectorIndex.Add(mem.ID, mem.Embedding)
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetSharder associa um Sharder (tipicamente um *hashring.HashRing) e o ID
+// desta réplica, habilitando o particionamento do Scan entre orquestradores.
+func (m *HybridMemoryManager) SetSharder(sharder Sharder, localID string) {
+	m.sharder = sharder
+	m.localID = localID
+}
+
+// owns retorna true se esta réplica deve considerar a chave informada,
+// segundo o Sharder configurado. Sem Sharder, toda chave é considerada.
+func (m *HybridMemoryManager) owns(key string) bool {
+	if m.sharder == nil || m.localID == "" {
+		return true
 	}
 
-	if err := m.mongoClient.Disconnect(ctx); err != nil {
-		return fmt.Errorf("erro ao fechar conexão com MongoDB: %v", err)
+	owner, ok := m.sharder.OwnerOf(key)
+	return !ok || owner == m.localID
+}
+
+// storeFor retorna o MemoryStore responsável pelo tipo de memória informado.
+func (m *HybridMemoryManager) storeFor(memType MemoryType) MemoryStore {
+	if memType == ShortTerm {
+		return m.shortStore
 	}
+	return m.longStore
+}
 
+func keyFor(agentID, memoryID string) string {
+	return fmt.Sprintf("memory:%s:%s", agentID, memoryID)
+}
+
+// Close fecha as duas camadas de armazenamento.
+func (m *HybridMemoryManager) Close(ctx context.Context) error {
+	if err := m.shortStore.Close(ctx); err != nil {
+		return fmt.Errorf("erro ao fechar store de curto prazo: %v", err)
+	}
+	if err := m.longStore.Close(ctx); err != nil {
+		return fmt.Errorf("erro ao fechar store de longo prazo: %v", err)
+	}
 	return nil
 }
 
-// StoreMemory armazena uma memória no Redis (curto prazo) ou MongoDB (longo prazo)
+// StoreMemory armazena uma memória na camada correspondente ao seu tipo,
+// calculando seu embedding (caso ainda não informado) e registrando-o no
+// índice vetorial para habilitar SearchMemoriesSemantic.
 func (m *HybridMemoryManager) StoreMemory(ctx context.Context, memory *Memory) error {
 	memory.CreatedAt = time.Now()
 
-	if memory.Type == ShortTerm {
-		// Armazena no Redis com TTL
-		data, err := json.Marshal(memory)
-		if err != nil {
-			return fmt.Errorf("erro ao serializar memória: %v", err)
-		}
+	if err := m.embed(ctx, memory); err != nil {
+		return fmt.Errorf("erro ao calcular embedding da memória: %v", err)
+	}
 
-		key := fmt.Sprintf("memory:%s:%s", memory.AgentID, memory.ID)
-		ttl := memory.TTL
-		if ttl == 0 {
-			ttl = m.config.ShortTermTTL
-		}
+	data, err := json.Marshal(memory)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar memória: %v", err)
+	}
 
-		if err := m.redisClient.Set(ctx, key, data, ttl).Err(); err != nil {
-			return fmt.Errorf("erro ao armazenar memória no Redis: %v", err)
-		}
-	} else {
-		// Armazena no MongoDB
-		if _, err := m.collection.InsertOne(ctx, memory); err != nil {
-			return fmt.Errorf("erro ao armazenar memória no MongoDB: %v", err)
-		}
+	ttl := memory.TTL
+	if memory.Type == ShortTerm && ttl == 0 {
+		ttl = m.config.ShortTermTTL
+	}
+
+	if err := m.storeFor(memory.Type).Set(ctx, keyFor(memory.AgentID, memory.ID), data, ttl); err != nil {
+		return fmt.Errorf("erro ao armazenar memória: %v", err)
+	}
+
+	if m.vectorIndex != nil && len(memory.Embedding) > 0 {
+		m.vectorIndex.Add(memory.ID, memory.Embedding)
 	}
 
 	return nil
 }
 
-// GetMemory recupera uma memória do Redis ou MongoDB
-func (m *HybridMemoryManager) GetMemory(ctx context.Context, agentID, memoryID string) (*Memory, error) {
-	// Tenta primeiro no Redis
-	key := fmt.Sprintf("memory:%s:%s", agentID, memoryID)
-	data, err := m.redisClient.Get(ctx, key).Bytes()
-	if err == nil {
-		var memory Memory
-		if err := json.Unmarshal(data, &memory); err != nil {
-			return nil, fmt.Errorf("erro ao deserializar memória do Redis: %v", err)
-		}
-		return &memory, nil
+// embed calcula memory.Embedding a partir de memory.Content via m.embedder,
+// caso ainda não tenha sido preenchido por quem chamou StoreMemory.
+func (m *HybridMemoryManager) embed(ctx context.Context, memory *Memory) error {
+	if len(memory.Embedding) > 0 || m.embedder == nil {
+		return nil
 	}
 
-	// Se não encontrou no Redis, busca no MongoDB
-	filter := bson.M{"agent_id": agentID, "_id": memoryID}
-	var memory Memory
-	if err := m.collection.FindOne(ctx, filter).Decode(&memory); err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("memória não encontrada")
-		}
-		return nil, fmt.Errorf("erro ao buscar memória no MongoDB: %v", err)
+	content, err := json.Marshal(memory.Content)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar conteúdo para embedding: %v", err)
+	}
+
+	embedding, err := m.embedder.Embed(ctx, string(content))
+	if err != nil {
+		return fmt.Errorf("erro ao chamar embedder: %v", err)
 	}
 
-	return &memory, nil
+	memory.Embedding = embedding
+	return nil
 }
 
-// SearchMemories busca memórias por tags no Redis e MongoDB
-func (m *HybridMemoryManager) SearchMemories(ctx context.Context, agentID string, tags []string) ([]*Memory, error) {
-	var memories []*Memory
+// GetMemory recupera uma memória, tentando primeiro a camada de curto prazo
+// e depois a de longo prazo, e devolve uma MemoryRef com uma lease viva que
+// impede sua remoção por ConsolidateMemories/PruneMemories até ser liberada.
+func (m *HybridMemoryManager) GetMemory(ctx context.Context, agentID, memoryID string) (*MemoryRef, error) {
+	key := keyFor(agentID, memoryID)
 
-	// Busca no Redis
+	data, err := m.shortStore.Get(ctx, key)
+	if err == ErrNotFound {
+		data, err = m.longStore.Get(ctx, key)
+	}
+	if err == ErrNotFound {
+		return nil, fmt.Errorf("memória não encontrada")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar memória: %v", err)
+	}
+
+	var memory Memory
+	if err := json.Unmarshal(data, &memory); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar memória: %v", err)
+	}
+
+	return m.newRef(ctx, agentID, &memory)
+}
+
+// SearchMemories busca memórias por tags em ambas as camadas, devolvendo uma
+// MemoryRef com lease viva para cada resultado.
+func (m *HybridMemoryManager) SearchMemories(ctx context.Context, agentID string, tags []string) ([]*MemoryRef, error) {
+	var refs []*MemoryRef
 	pattern := fmt.Sprintf("memory:%s:*", agentID)
-	iter := m.redisClient.Scan(ctx, 0, pattern, 0).Iterator()
-	for iter.Next(ctx) {
-		data, err := m.redisClient.Get(ctx, iter.Val()).Bytes()
+
+	for _, store := range []MemoryStore{m.shortStore, m.longStore} {
+		keys, err := store.Scan(ctx, pattern)
 		if err != nil {
-			continue
+			return refs, fmt.Errorf("erro ao buscar memórias: %v", err)
 		}
 
-		var memory Memory
-		if err := json.Unmarshal(data, &memory); err != nil {
-			continue
-		}
+		for _, key := range keys {
+			// Pula chaves que pertencem a outra réplica segundo o hashring,
+			// particionando a busca entre as instâncias do orquestrador.
+			if !m.owns(key) {
+				continue
+			}
 
-		// Verifica se a memória tem todas as tags buscadas
-		hasAllTags := true
-		for _, tag := range tags {
-			found := false
-			for _, memTag := range memory.Tags {
-				if memTag == tag {
-					found = true
-					break
-				}
+			data, err := store.Get(ctx, key)
+			if err != nil {
+				continue
 			}
-			if !found {
-				hasAllTags = false
-				break
+
+			var memory Memory
+			if err := json.Unmarshal(data, &memory); err != nil {
+				continue
 			}
-		}
 
-		if hasAllTags {
-			memories = append(memories, &memory)
+			if !hasAllTags(&memory, tags) {
+				continue
+			}
+
+			ref, err := m.newRef(ctx, agentID, &memory)
+			if err != nil {
+				continue
+			}
+			refs = append(refs, ref)
 		}
 	}
 
-	// Busca no MongoDB
-	filter := bson.M{
-		"agent_id": agentID,
-		"tags":     bson.M{"$all": tags},
+	return refs, nil
+}
+
+// SearchMemoriesSemantic busca memórias por proximidade semântica ao texto de
+// query, reordenando os candidatos do índice vetorial por Importance e
+// AccessCount e filtrando o resultado pelas tags em tagFilter (quando
+// informadas). Requer VectorIndexType == "flat"; com "mongo_atlas" a busca
+// seria delegada a uma agregação $vectorSearch no longStore, ainda não
+// implementada.
+func (m *HybridMemoryManager) SearchMemoriesSemantic(ctx context.Context, agentID, query string, k int, tagFilter []string) ([]*Memory, error) {
+	if m.vectorIndex == nil {
+		return nil, fmt.Errorf("busca semântica indisponível: VectorIndexType=%s não mantém um índice local", m.config.VectorIndexType)
 	}
-	cursor, err := m.collection.Find(ctx, filter)
+
+	queryEmbedding, err := m.embedder.Embed(ctx, query)
 	if err != nil {
-		return memories, fmt.Errorf("erro ao buscar memórias no MongoDB: %v", err)
+		return nil, fmt.Errorf("erro ao calcular embedding da consulta: %v", err)
 	}
-	defer cursor.Close(ctx)
 
-	for cursor.Next(ctx) {
-		var memory Memory
-		if err := cursor.Decode(&memory); err != nil {
+	// Busca uma margem maior de candidatos do que k, já que o pós-filtro por
+	// agentID/tags e o reranking por Importance/AccessCount pode descartar
+	// alguns dos vizinhos mais próximos.
+	candidateIDs := m.vectorIndex.Search(queryEmbedding, k*4)
+
+	type scored struct {
+		memory *Memory
+		score  float64
+	}
+	var candidates []scored
+
+	for _, id := range candidateIDs {
+		key := keyFor(agentID, id)
+
+		data, err := m.shortStore.Get(ctx, key)
+		if err == ErrNotFound {
+			data, err = m.longStore.Get(ctx, key)
+		}
+		if err != nil {
+			continue
+		}
+
+		var mem Memory
+		if err := json.Unmarshal(data, &mem); err != nil {
 			continue
 		}
-		memories = append(memories, &memory)
+
+		if mem.AgentID != agentID {
+			continue
+		}
+		if !hasAllTags(&mem, tagFilter) {
+			continue
+		}
+
+		// Reranking: combina a similaridade (implícita na ordem devolvida pelo
+		// índice) com Importance e AccessCount, para que memórias relevantes e
+		// frequentemente acessadas superem vizinhos marginalmente mais
+		// próximos, porém pouco usados.
+		score := mem.Importance + float64(mem.AccessCount)*0.01
+		candidates = append(candidates, scored{memory: &mem, score: score})
 	}
 
-	return memories, nil
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+	if k > len(candidates) {
+		k = len(candidates)
+	}
+
+	results := make([]*Memory, k)
+	for i := 0; i < k; i++ {
+		results[i] = candidates[i].memory
+	}
+	return results, nil
+}
+
+func hasAllTags(memory *Memory, tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, memTag := range memory.Tags {
+			if memTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
-// ConsolidateMemories move memórias importantes de curto prazo para longo prazo
+// ConsolidateMemories move memórias importantes da camada de curto prazo
+// para a de longo prazo — um move genérico entre stores, e não mais uma
+// transferência hardcoded de Redis para Mongo.
 func (m *HybridMemoryManager) ConsolidateMemories(ctx context.Context, agentID string) error {
 	pattern := fmt.Sprintf("memory:%s:*", agentID)
-	iter := m.redisClient.Scan(ctx, 0, pattern, 0).Iterator()
 
-	for iter.Next(ctx) {
-		data, err := m.redisClient.Get(ctx, iter.Val()).Bytes()
+	keys, err := m.shortStore.Scan(ctx, pattern)
+	if err != nil {
+		return fmt.Errorf("erro ao varrer memórias de curto prazo: %v", err)
+	}
+
+	for _, key := range keys {
+		data, err := m.shortStore.Get(ctx, key)
 		if err != nil {
 			continue
 		}
@@ -225,86 +369,159 @@ func (m *HybridMemoryManager) ConsolidateMemories(ctx context.Context, agentID s
 			continue
 		}
 
-		// Se a memória é importante o suficiente, move para longo prazo
-		if memory.Importance >= m.config.ImportanceThreshold {
-			memory.Type = LongTerm
-			if err := m.StoreMemory(ctx, &memory); err != nil {
-				continue
-			}
-			m.redisClient.Del(ctx, iter.Val())
+		if memory.Importance < m.config.ImportanceThreshold {
+			continue
+		}
+
+		// Não migra uma memória que ainda esteja sendo lida por outra
+		// goroutine através de uma MemoryRef viva.
+		if m.hasLiveLease(ctx, memory.ID) {
+			continue
+		}
+
+		// Antes de duplicar, verifica se já existe uma memória de longo prazo
+		// semanticamente equivalente (cosseno > 0.9): nesse caso funde o
+		// Content nela em vez de criar uma nova entrada.
+		if merged, err := m.mergeIntoCluster(ctx, &memory); err != nil {
+			continue
+		} else if merged {
+			_ = m.shortStore.Delete(ctx, key)
+			continue
 		}
+
+		memory.Type = LongTerm
+		if err := m.StoreMemory(ctx, &memory); err != nil {
+			continue
+		}
+		_ = m.shortStore.Delete(ctx, key)
 	}
 
 	return nil
 }
 
-// PruneMemories remove memórias antigas ou irrelevantes
+// mergeIntoCluster procura, entre os vizinhos semânticos de memory no índice
+// vetorial, uma memória de longo prazo já consolidada com similaridade de
+// cosseno acima de 0.9. Se encontrar, funde o Content de memory nela e
+// retorna true; caso contrário retorna false sem efeito colateral.
+func (m *HybridMemoryManager) mergeIntoCluster(ctx context.Context, memory *Memory) (bool, error) {
+	if m.vectorIndex == nil || len(memory.Embedding) == 0 {
+		return false, nil
+	}
+
+	pattern := fmt.Sprintf("memory:%s:*", memory.AgentID)
+	for _, id := range m.vectorIndex.Search(memory.Embedding, 5) {
+		key := keyFor(memory.AgentID, id)
+
+		data, err := m.longStore.Get(ctx, key)
+		if err != nil {
+			continue
+		}
+
+		var existing Memory
+		if err := json.Unmarshal(data, &existing); err != nil {
+			continue
+		}
+		if existing.Type != LongTerm || len(existing.Embedding) == 0 {
+			continue
+		}
+		if cosineSimilarity(memory.Embedding, existing.Embedding) <= 0.9 {
+			continue
+		}
+
+		for k, v := range memory.Content {
+			existing.Content[k] = v
+		}
+		existing.Tags = append(existing.Tags, memory.Tags...)
+		if memory.Importance > existing.Importance {
+			existing.Importance = memory.Importance
+		}
+
+		if err := m.UpdateMemory(ctx, &existing); err != nil {
+			return false, fmt.Errorf("erro ao fundir memórias em %s: %v", pattern, err)
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// PruneMemories remove da camada de longo prazo as memórias que são ao mesmo
+// tempo antigas (mais de 30 dias) e pouco importantes (abaixo de
+// ImportanceThreshold), exceto as que ainda têm lease viva.
 func (m *HybridMemoryManager) PruneMemories(ctx context.Context, agentID string) error {
-	// Remove memórias antigas do MongoDB
+	pattern := fmt.Sprintf("memory:%s:*", agentID)
 	threshold := time.Now().Add(-30 * 24 * time.Hour) // 30 dias
-	filter := bson.M{
-		"agent_id":   agentID,
-		"created_at": bson.M{"$lt": threshold},
-		"importance": bson.M{"$lt": m.config.ImportanceThreshold},
+
+	keys, err := m.longStore.Scan(ctx, pattern)
+	if err != nil {
+		return fmt.Errorf("erro ao varrer memórias de longo prazo: %v", err)
 	}
 
-	if _, err := m.collection.DeleteMany(ctx, filter); err != nil {
-		return fmt.Errorf("erro ao remover memórias antigas do MongoDB: %v", err)
+	for _, key := range keys {
+		data, err := m.longStore.Get(ctx, key)
+		if err != nil {
+			continue
+		}
+
+		var memory Memory
+		if err := json.Unmarshal(data, &memory); err != nil {
+			continue
+		}
+
+		if memory.CreatedAt.Before(threshold) && memory.Importance < m.config.ImportanceThreshold {
+			// Não remove uma memória com lease viva: alguém ainda pode estar
+			// no meio de uma leitura.
+			if m.hasLiveLease(ctx, memory.ID) {
+				continue
+			}
+
+			if err := m.longStore.Delete(ctx, key); err != nil {
+				return fmt.Errorf("erro ao remover memória antiga %s: %v", key, err)
+			}
+		}
 	}
 
 	return nil
 }
 
-// DeleteMemory remove uma memória específica
+// DeleteMemory remove uma memória específica de ambas as camadas.
 func (m *HybridMemoryManager) DeleteMemory(ctx context.Context, agentID, memoryID string) error {
-	// Remove do Redis
-	key := fmt.Sprintf("memory:%s:%s", agentID, memoryID)
-	if err := m.redisClient.Del(ctx, key).Err(); err != nil && err != redis.Nil {
-		return fmt.Errorf("erro ao remover memória do Redis: %v", err)
-	}
+	key := keyFor(agentID, memoryID)
 
-	// Remove do MongoDB
-	filter := bson.M{
-		"agent_id": agentID,
-		"_id":      memoryID,
+	if err := m.shortStore.Delete(ctx, key); err != nil {
+		return fmt.Errorf("erro ao remover memória: %v", err)
 	}
-	if _, err := m.collection.DeleteOne(ctx, filter); err != nil {
-		return fmt.Errorf("erro ao remover memória do MongoDB: %v", err)
+	if err := m.longStore.Delete(ctx, key); err != nil {
+		return fmt.Errorf("erro ao remover memória: %v", err)
 	}
-
 	return nil
 }
 
-// UpdateMemory atualiza uma memória existente
+// UpdateMemory atualiza uma memória existente na camada correspondente ao
+// seu tipo.
 func (m *HybridMemoryManager) UpdateMemory(ctx context.Context, memory *Memory) error {
-	// Atualiza no Redis se for memória de curto prazo
-	if memory.Type == ShortTerm {
-		data, err := json.Marshal(memory)
-		if err != nil {
-			return fmt.Errorf("erro ao serializar memória: %v", err)
-		}
-
-		key := fmt.Sprintf("memory:%s:%s", memory.AgentID, memory.ID)
-		ttl := memory.TTL
-		if ttl == 0 {
-			ttl = m.config.ShortTermTTL
-		}
-
-		if err := m.redisClient.Set(ctx, key, data, ttl).Err(); err != nil {
-			return fmt.Errorf("erro ao atualizar memória no Redis: %v", err)
-		}
-	} else {
-		// Atualiza no MongoDB
-		filter := bson.M{
-			"agent_id": memory.AgentID,
-			"_id":      memory.ID,
-		}
-		update := bson.M{"$set": memory}
+	data, err := json.Marshal(memory)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar memória: %v", err)
+	}
 
-		if _, err := m.collection.UpdateOne(ctx, filter, update); err != nil {
-			return fmt.Errorf("erro ao atualizar memória no MongoDB: %v", err)
-		}
+	ttl := memory.TTL
+	if memory.Type == ShortTerm && ttl == 0 {
+		ttl = m.config.ShortTermTTL
 	}
 
+	if err := m.storeFor(memory.Type).Set(ctx, keyFor(memory.AgentID, memory.ID), data, ttl); err != nil {
+		return fmt.Errorf("erro ao atualizar memória: %v", err)
+	}
 	return nil
 }
+
+// Stats retorna as estatísticas de hit/miss por camada, quando o store de
+// curto prazo for um TieredStore. Caso contrário retorna um StoreStats
+// zerado, já que os stores simples não acumulam esses contadores.
+func (m *HybridMemoryManager) Stats() StoreStats {
+	if tiered, ok := m.shortStore.(*TieredStore); ok {
+		return tiered.Stats()
+	}
+	return StoreStats{}
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MemoryStore é a interface de baixo nível para um backend de armazenamento
+// de memórias (in-process, Redis, Mongo, ...). O MemoryManager é composto a
+// partir de duas implementações desta interface — uma para curto prazo e
+// outra para longo prazo — em vez de acoplar diretamente a um cliente
+// concreto, seguindo o pacote de cache plugável do Cortex (pkg/chunk/cache).
+type MemoryStore interface {
+	// Get recupera o valor bruto associado à chave. Retorna ErrNotFound se a
+	// chave não existir ou já tiver expirado.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set grava o valor associado à chave. ttl == 0 significa sem expiração.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete remove a chave, sem erro caso ela não exista.
+	Delete(ctx context.Context, key string) error
+
+	// Scan lista as chaves que casam com o padrão (estilo glob do Redis,
+	// ex.: "memory:agent1:*").
+	Scan(ctx context.Context, pattern string) ([]string, error)
+
+	// TTL retorna o tempo restante de vida da chave. Retorna 0 para chaves
+	// sem expiração.
+	TTL(ctx context.Context, key string) (time.Duration, error)
+
+	// Close libera quaisquer recursos associados ao store.
+	Close(ctx context.Context) error
+}
+
+// ErrNotFound é retornado por MemoryStore.Get quando a chave não existe.
+var ErrNotFound = fmt.Errorf("chave não encontrada")
+
+// NewMemoryStore constrói o MemoryStore identificado por backend ("memory",
+// "redis", "mongo" ou "tiered") usando a configuração informada.
+func NewMemoryStore(ctx context.Context, backend string, config *MemoryConfig) (MemoryStore, error) {
+	switch backend {
+	case "memory", "":
+		capacity := config.InMemoryCapacity
+		if capacity <= 0 {
+			capacity = 1000
+		}
+		return NewInMemoryStore(capacity), nil
+	case "redis":
+		return NewRedisStore(ctx, config.RedisURL)
+	case "mongo":
+		return NewMongoStore(ctx, config.MongoURL, config.MongoDB, config.Collection)
+	case "tiered":
+		hot, err := NewMemoryStore(ctx, config.TieredHotBackend, config)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao criar camada quente do tiered store (%s): %v", config.TieredHotBackend, err)
+		}
+		cold, err := NewMemoryStore(ctx, config.TieredColdBackend, config)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao criar camada fria do tiered store (%s): %v", config.TieredColdBackend, err)
+		}
+		return NewTieredStore(hot, cold, config.PromotionThreshold), nil
+	default:
+		return nil, fmt.Errorf("backend de memória desconhecido: %s", backend)
+	}
+}
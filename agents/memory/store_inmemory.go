@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// InMemoryStore é um MemoryStore em processo, usado para contexto efêmero de
+// curta duração onde não vale a pena pagar o custo de uma ida à rede. Evicta
+// pela política LRU quando a capacidade é excedida.
+type InMemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type inMemoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // zero value == sem expiração
+}
+
+// NewInMemoryStore cria um InMemoryStore com a capacidade informada.
+func NewInMemoryStore(capacity int) *InMemoryStore {
+	return &InMemoryStore{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get recupera o valor da chave, promovendo-a para o topo do LRU.
+func (s *InMemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.entries[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	entry := el.Value.(*inMemoryEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		s.removeElement(el)
+		return nil, ErrNotFound
+	}
+
+	s.order.MoveToFront(el)
+	return entry.value, nil
+}
+
+// Set grava o valor, evictando a entrada menos recentemente usada se a
+// capacidade for excedida.
+func (s *InMemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if el, ok := s.entries[key]; ok {
+		entry := el.Value.(*inMemoryEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		s.order.MoveToFront(el)
+		return nil
+	}
+
+	el := s.order.PushFront(&inMemoryEntry{key: key, value: value, expiresAt: expiresAt})
+	s.entries[key] = el
+
+	if s.capacity > 0 && s.order.Len() > s.capacity {
+		s.removeElement(s.order.Back())
+	}
+
+	return nil
+}
+
+// Delete remove a chave, se existir.
+func (s *InMemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		s.removeElement(el)
+	}
+	return nil
+}
+
+// Scan retorna as chaves que casam com o padrão glob informado.
+func (s *InMemoryStore) Scan(ctx context.Context, pattern string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for el := s.order.Front(); el != nil; el = el.Next() {
+		entry := el.Value.(*inMemoryEntry)
+		if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+			continue
+		}
+		if matched, _ := filepath.Match(pattern, entry.key); matched {
+			keys = append(keys, entry.key)
+		}
+	}
+	return keys, nil
+}
+
+// TTL retorna o tempo restante de vida da chave.
+func (s *InMemoryStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.entries[key]
+	if !ok {
+		return 0, ErrNotFound
+	}
+
+	entry := el.Value.(*inMemoryEntry)
+	if entry.expiresAt.IsZero() {
+		return 0, nil
+	}
+	return time.Until(entry.expiresAt), nil
+}
+
+// Close não faz nada além de satisfazer a interface MemoryStore.
+func (s *InMemoryStore) Close(ctx context.Context) error {
+	return nil
+}
+
+// removeElement deve ser chamado com s.mu já travado.
+func (s *InMemoryStore) removeElement(el *list.Element) {
+	entry := el.Value.(*inMemoryEntry)
+	delete(s.entries, entry.key)
+	s.order.Remove(el)
+}
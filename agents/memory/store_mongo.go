@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoDoc é o documento genérico usado pelo MongoStore para representar um
+// par chave/valor, já que MemoryStore lida com []byte e não com o esquema
+// estruturado de Memory.
+type mongoDoc struct {
+	Key       string    `bson:"_id"`
+	Value     []byte    `bson:"value"`
+	ExpiresAt time.Time `bson:"expires_at,omitempty"`
+}
+
+// MongoStore é um MemoryStore sobre uma coleção do MongoDB.
+type MongoStore struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+// NewMongoStore conecta ao MongoDB e abre a coleção informada.
+func NewMongoStore(ctx context.Context, mongoURL, database, collection string) (*MongoStore, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURL))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao MongoDB: %v", err)
+	}
+
+	if err := client.Ping(ctx, nil); err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao MongoDB: %v", err)
+	}
+
+	return &MongoStore{
+		client:     client,
+		collection: client.Database(database).Collection(collection),
+	}, nil
+}
+
+// Get recupera o valor da chave, tratando documentos expirados como ausentes.
+func (s *MongoStore) Get(ctx context.Context, key string) ([]byte, error) {
+	var doc mongoDoc
+	if err := s.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar chave %s no MongoDB: %v", key, err)
+	}
+
+	if !doc.ExpiresAt.IsZero() && time.Now().After(doc.ExpiresAt) {
+		_, _ = s.collection.DeleteOne(ctx, bson.M{"_id": key})
+		return nil, ErrNotFound
+	}
+
+	return doc.Value, nil
+}
+
+// Set grava o valor da chave, com expiração opcional.
+func (s *MongoStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	doc := mongoDoc{Key: key, Value: value}
+	if ttl > 0 {
+		doc.ExpiresAt = time.Now().Add(ttl)
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.collection.ReplaceOne(ctx, bson.M{"_id": key}, doc, opts); err != nil {
+		return fmt.Errorf("erro ao gravar chave %s no MongoDB: %v", key, err)
+	}
+	return nil
+}
+
+// Delete remove a chave.
+func (s *MongoStore) Delete(ctx context.Context, key string) error {
+	if _, err := s.collection.DeleteOne(ctx, bson.M{"_id": key}); err != nil {
+		return fmt.Errorf("erro ao remover chave %s do MongoDB: %v", key, err)
+	}
+	return nil
+}
+
+// Scan lista as chaves que casam com o padrão glob informado, traduzindo-o
+// para uma regex ancorada antes de consultar o MongoDB.
+func (s *MongoStore) Scan(ctx context.Context, pattern string) ([]string, error) {
+	filter := bson.M{"_id": bson.M{"$regex": globToRegex(pattern)}}
+
+	cursor, err := s.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao varrer padrão %s no MongoDB: %v", pattern, err)
+	}
+	defer cursor.Close(ctx)
+
+	var keys []string
+	for cursor.Next(ctx) {
+		var doc mongoDoc
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+		keys = append(keys, doc.Key)
+	}
+	return keys, nil
+}
+
+// TTL retorna o tempo restante de vida da chave.
+func (s *MongoStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	var doc mongoDoc
+	if err := s.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, ErrNotFound
+		}
+		return 0, fmt.Errorf("erro ao consultar TTL da chave %s: %v", key, err)
+	}
+
+	if doc.ExpiresAt.IsZero() {
+		return 0, nil
+	}
+	return time.Until(doc.ExpiresAt), nil
+}
+
+// Close encerra a conexão com o MongoDB.
+func (s *MongoStore) Close(ctx context.Context) error {
+	if err := s.client.Disconnect(ctx); err != nil {
+		return fmt.Errorf("erro ao fechar conexão com MongoDB: %v", err)
+	}
+	return nil
+}
+
+// globToRegex converte um padrão glob simples (apenas "*") em uma regex
+// ancorada, para uso em consultas $regex do MongoDB.
+func globToRegex(pattern string) string {
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return "^" + strings.Join(parts, ".*") + "$"
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisStore é um MemoryStore sobre o Redis.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore conecta ao Redis na URL informada.
+func NewRedisStore(ctx context.Context, redisURL string) (*RedisStore, error) {
+	opt, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao parsear URL do Redis: %v", err)
+	}
+
+	client := redis.NewClient(opt)
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao Redis: %v", err)
+	}
+
+	return &RedisStore{client: client}, nil
+}
+
+// Get recupera o valor da chave.
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler chave %s do Redis: %v", key, err)
+	}
+	return data, nil
+}
+
+// Set grava o valor da chave com o TTL informado.
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := s.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("erro ao gravar chave %s no Redis: %v", key, err)
+	}
+	return nil
+}
+
+// Delete remove a chave.
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, key).Err(); err != nil && err != redis.Nil {
+		return fmt.Errorf("erro ao remover chave %s do Redis: %v", key, err)
+	}
+	return nil
+}
+
+// Scan varre o keyspace em busca de chaves que casam com o padrão.
+func (s *RedisStore) Scan(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	iter := s.client.Scan(ctx, 0, pattern, 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao varrer padrão %s no Redis: %v", pattern, err)
+	}
+	return keys, nil
+}
+
+// TTL retorna o tempo restante de vida da chave.
+func (s *RedisStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.client.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao consultar TTL da chave %s: %v", key, err)
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// Close encerra a conexão com o Redis.
+func (s *RedisStore) Close(ctx context.Context) error {
+	if err := s.client.Close(); err != nil {
+		return fmt.Errorf("erro ao fechar conexão com Redis: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// StoreStats acumula contadores de hit/miss por camada, para que o agente
+// observador possa raspá-los e acompanhar a eficácia do cache.
+type StoreStats struct {
+	HotHits    int64
+	HotMisses  int64
+	ColdHits   int64
+	ColdMisses int64
+	Promotions int64
+}
+
+// TieredStore empilha dois MemoryStore — um quente (hot) e um frio (cold) —
+// por trás de uma única interface MemoryStore, com leitura e escrita
+// atravessando ambas as camadas e promoção do frio para o quente quando uma
+// chave é acessada com frequência, no espírito do cache plugável do Cortex.
+type TieredStore struct {
+	hot       MemoryStore
+	cold      MemoryStore
+	threshold int
+
+	mu          sync.Mutex
+	accessCount map[string]int
+	stats       StoreStats
+}
+
+// NewTieredStore empilha hot sobre cold, promovendo uma chave da camada fria
+// para a quente após promotionThreshold acessos.
+func NewTieredStore(hot, cold MemoryStore, promotionThreshold int) *TieredStore {
+	if promotionThreshold <= 0 {
+		promotionThreshold = 1
+	}
+
+	return &TieredStore{
+		hot:         hot,
+		cold:        cold,
+		threshold:   promotionThreshold,
+		accessCount: make(map[string]int),
+	}
+}
+
+// Get lê primeiro da camada quente (read-through); se ausente, busca na fria
+// e promove a chave para a quente após threshold acessos.
+func (t *TieredStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, err := t.hot.Get(ctx, key); err == nil {
+		t.mu.Lock()
+		t.stats.HotHits++
+		t.mu.Unlock()
+		return value, nil
+	} else if err != ErrNotFound {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.stats.HotMisses++
+	t.mu.Unlock()
+
+	value, err := t.cold.Get(ctx, key)
+	if err != nil {
+		if err == ErrNotFound {
+			t.mu.Lock()
+			t.stats.ColdMisses++
+			t.mu.Unlock()
+		}
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.stats.ColdHits++
+	t.accessCount[key]++
+	shouldPromote := t.accessCount[key] >= t.threshold
+	if shouldPromote {
+		delete(t.accessCount, key)
+		t.stats.Promotions++
+	}
+	t.mu.Unlock()
+
+	if shouldPromote {
+		ttl, _ := t.cold.TTL(ctx, key)
+		_ = t.hot.Set(ctx, key, value, ttl)
+	}
+
+	return value, nil
+}
+
+// Set grava em ambas as camadas (write-through).
+func (t *TieredStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := t.cold.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	return t.hot.Set(ctx, key, value, ttl)
+}
+
+// Delete remove a chave de ambas as camadas.
+func (t *TieredStore) Delete(ctx context.Context, key string) error {
+	if err := t.hot.Delete(ctx, key); err != nil {
+		return err
+	}
+	return t.cold.Delete(ctx, key)
+}
+
+// Scan retorna a união das chaves de ambas as camadas que casam com o padrão.
+func (t *TieredStore) Scan(ctx context.Context, pattern string) ([]string, error) {
+	seen := make(map[string]bool)
+	var keys []string
+
+	hotKeys, err := t.hot.Scan(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range hotKeys {
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+
+	coldKeys, err := t.cold.Scan(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range coldKeys {
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+
+	return keys, nil
+}
+
+// TTL consulta a camada quente primeiro, caindo para a fria.
+func (t *TieredStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	if ttl, err := t.hot.TTL(ctx, key); err == nil {
+		return ttl, nil
+	}
+	return t.cold.TTL(ctx, key)
+}
+
+// Close fecha ambas as camadas.
+func (t *TieredStore) Close(ctx context.Context) error {
+	if err := t.hot.Close(ctx); err != nil {
+		return err
+	}
+	return t.cold.Close(ctx)
+}
+
+// Stats retorna uma cópia dos contadores de hit/miss acumulados até agora.
+func (t *TieredStore) Stats() StoreStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stats
+}
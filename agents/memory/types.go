@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -25,6 +26,21 @@ type Memory struct {
 	LastAccess  time.Time              `json:"last_access" bson:"last_access"`
 	TTL         time.Duration          `json:"ttl" bson:"ttl"`
 	Tags        []string               `json:"tags" bson:"tags"`
+	Embedding   []float32              `json:"embedding,omitempty" bson:"embedding,omitempty"`
+
+	// manager referencia o gerenciador de origem para que Finalize saiba
+	// onde comitar a memória. Não é serializado.
+	manager *HybridMemoryManager `json:"-" bson:"-"`
+}
+
+// Finalize comita uma memória de curto prazo para o armazenamento de longo
+// prazo atomicamente, espelhando a semântica de Finalize do BuildKit
+// (cache/refs.go) para refs imutáveis.
+func (mem *Memory) Finalize(ctx context.Context) error {
+	if mem.manager == nil {
+		return fmt.Errorf("memória não está associada a um gerenciador, não é possível finalizar")
+	}
+	return mem.manager.finalize(ctx, mem)
 }
 
 // MemoryManager gerencia o armazenamento e recuperação de memórias
@@ -32,11 +48,12 @@ type MemoryManager interface {
 	// StoreMemory armazena uma nova memória
 	StoreMemory(ctx context.Context, memory *Memory) error
 
-	// GetMemory recupera uma memória pelo ID
-	GetMemory(ctx context.Context, agentID, memoryID string) (*Memory, error)
+	// GetMemory recupera uma memória pelo ID, com uma lease que impede sua
+	// remoção por ConsolidateMemories/PruneMemories enquanto estiver viva
+	GetMemory(ctx context.Context, agentID, memoryID string) (*MemoryRef, error)
 
-	// SearchMemories busca memórias por tags
-	SearchMemories(ctx context.Context, agentID string, tags []string) ([]*Memory, error)
+	// SearchMemories busca memórias por tags, cada uma com sua própria lease
+	SearchMemories(ctx context.Context, agentID string, tags []string) ([]*MemoryRef, error)
 
 	// UpdateMemory atualiza uma memória existente
 	UpdateMemory(ctx context.Context, memory *Memory) error
@@ -54,6 +71,13 @@ type MemoryManager interface {
 	Close(ctx context.Context) error
 }
 
+// Sharder decide qual réplica é dona de uma chave, usado para particionar o
+// SCAN do Redis entre múltiplas instâncias do orquestrador sem que cada uma
+// precise varrer o keyspace inteiro. Implementado por *hashring.HashRing.
+type Sharder interface {
+	OwnerOf(key string) (string, bool)
+}
+
 // MemoryConfig contém as configurações para o gerenciador de memória
 type MemoryConfig struct {
 	RedisURL            string        // URL de conexão com o Redis
@@ -62,6 +86,41 @@ type MemoryConfig struct {
 	Collection          string        // Nome da coleção MongoDB
 	ShortTermTTL        time.Duration // Tempo de vida padrão para memórias de curto prazo
 	ImportanceThreshold float64       // Limiar de importância para consolidação
+
+	// ShortTermBackend e LongTermBackend escolhem, entre "memory", "redis" e
+	// "mongo", qual MemoryStore compõe cada camada do HybridMemoryManager.
+	ShortTermBackend string
+	LongTermBackend  string
+
+	// InMemoryCapacity limita o número de entradas do InMemoryStore antes de
+	// começar a evictar pelo critério de LRU.
+	InMemoryCapacity int
+
+	// PromotionThreshold é o número de acessos ao dado na camada fria antes
+	// de promovê-lo para a camada quente em um TieredStore.
+	PromotionThreshold int
+
+	// TieredHotBackend e TieredColdBackend escolhem, entre "memory", "redis"
+	// e "mongo", os MemoryStore que compõem as camadas quente e fria quando
+	// ShortTermBackend/LongTermBackend é "tiered".
+	TieredHotBackend  string
+	TieredColdBackend string
+
+	// LeaseTTL é por quanto tempo uma lease de memória (ver MemoryRef) fica
+	// viva sem renovação antes de ser reapada.
+	LeaseTTL time.Duration
+
+	// EmbedderProvider escolhe o provedor de embeddings usado em
+	// SearchMemoriesSemantic: "openai", "local" ou "fake".
+	EmbedderProvider string
+
+	// EmbedderModel é o modelo a pedir ao provedor de embeddings (ex.:
+	// "text-embedding-3-small" para o provedor "openai").
+	EmbedderModel string
+
+	// VectorIndexType escolhe onde o índice de vizinhos mais próximos vive:
+	// "flat" (em memória, busca exaustiva) ou "mongo_atlas" ($vectorSearch).
+	VectorIndexType string
 }
 
 // DefaultMemoryConfig retorna uma configuração padrão para o gerenciador de memória
@@ -73,5 +132,15 @@ func DefaultMemoryConfig() *MemoryConfig {
 		Collection:          "memories",
 		ShortTermTTL:        24 * time.Hour,
 		ImportanceThreshold: 0.7,
+		ShortTermBackend:    "redis",
+		LongTermBackend:     "mongo",
+		InMemoryCapacity:    1000,
+		PromotionThreshold:  3,
+		TieredHotBackend:    "memory",
+		TieredColdBackend:   "redis",
+		LeaseTTL:            60 * time.Second,
+		EmbedderProvider:    "fake",
+		EmbedderModel:       "text-embedding-3-small",
+		VectorIndexType:     "flat",
 	}
 }
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// VectorIndex resolve buscas por vizinhos mais próximos sobre embeddings.
+type VectorIndex interface {
+	// Add insere ou substitui o embedding associado a um ID.
+	Add(id string, embedding []float32)
+
+	// Remove retira um ID do índice.
+	Remove(id string)
+
+	// Search retorna até k IDs mais próximos do embedding de consulta,
+	// ordenados por similaridade de cosseno decrescente.
+	Search(query []float32, k int) []string
+}
+
+// FlatIndex é um índice exaustivo (brute-force) em memória: compara a
+// consulta contra todos os embeddings conhecidos. Suficiente para o volume
+// de memórias de um único agente; para coleções maiores, trocar por um
+// índice HNSW implementando a mesma interface.
+type FlatIndex struct {
+	mu         sync.RWMutex
+	embeddings map[string][]float32
+}
+
+// NewFlatIndex cria um FlatIndex vazio.
+func NewFlatIndex() *FlatIndex {
+	return &FlatIndex{embeddings: make(map[string][]float32)}
+}
+
+// Add insere ou substitui o embedding de um ID.
+func (idx *FlatIndex) Add(id string, embedding []float32) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.embeddings[id] = embedding
+}
+
+// Remove retira um ID do índice.
+func (idx *FlatIndex) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	delete(idx.embeddings, id)
+}
+
+// Search varre todos os embeddings conhecidos e retorna os k mais próximos
+// da consulta por similaridade de cosseno.
+func (idx *FlatIndex) Search(query []float32, k int) []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	type scored struct {
+		id    string
+		score float32
+	}
+
+	candidates := make([]scored, 0, len(idx.embeddings))
+	for id, emb := range idx.embeddings {
+		candidates = append(candidates, scored{id: id, score: cosineSimilarity(query, emb)})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+	if k > len(candidates) {
+		k = len(candidates)
+	}
+
+	ids := make([]string, k)
+	for i := 0; i < k; i++ {
+		ids[i] = candidates[i].id
+	}
+	return ids
+}
+
+// cosineSimilarity calcula a similaridade de cosseno entre dois vetores.
+// Retorna 0 se tiverem tamanhos diferentes ou norma nula.
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
@@ -0,0 +1,192 @@
+package agents
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// ObserverMetrics agrupa as séries Prometheus publicadas pelo
+// ObserverInfrastructureAgent. O Registerer é injetado pelo chamador — no
+// espírito do Registerer do Cortex — para que um processo real use o
+// prometheus.DefaultRegisterer e testes possam usar um prometheus.NewRegistry
+// isolado sem colidir entre execuções.
+type ObserverMetrics struct {
+	cpuUsage       prometheus.Gauge
+	memoryUsage    prometheus.Gauge
+	memoryAlloc    prometheus.Gauge
+	memorySys      prometheus.Gauge
+	goroutines     prometheus.Gauge
+	taskQueueDepth prometheus.Gauge
+	errorsTotal    prometheus.Counter
+	scaleEvents    prometheus.Counter
+
+	storeHits   *prometheus.CounterVec
+	storeMisses *prometheus.CounterVec
+
+	backendLatency *prometheus.HistogramVec
+
+	cpuThreshold    prometheus.Gauge
+	memoryThreshold prometheus.Gauge
+	tasksThreshold  prometheus.Gauge
+	errorThreshold  prometheus.Gauge
+
+	placementScore  *prometheus.GaugeVec
+	spreadDeviation *prometheus.GaugeVec
+
+	// lastHot/lastCold registram o último valor absoluto dos contadores do
+	// StoreStats já repassado aos CounterVec, já que Prometheus exige
+	// contadores monotônicos e StoreStats é reamostrado a cada scrape.
+	lastHotHits, lastHotMisses   int64
+	lastColdHits, lastColdMisses int64
+}
+
+// NewObserverMetrics registra e retorna o conjunto de métricas do observador
+// no Registerer informado.
+func NewObserverMetrics(reg prometheus.Registerer) *ObserverMetrics {
+	factory := promauto.With(reg)
+
+	m := &ObserverMetrics{
+		cpuUsage: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_observer_cpu_usage_percent",
+			Help: "Uso de CPU estimado do processo, em percentual.",
+		}),
+		memoryUsage: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_observer_memory_usage_percent",
+			Help: "Uso de memória estimado do processo, em percentual.",
+		}),
+		memoryAlloc: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_observer_memory_alloc_bytes",
+			Help: "Bytes alocados e ainda em uso, de runtime.MemStats.Alloc.",
+		}),
+		memorySys: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_observer_memory_sys_bytes",
+			Help: "Bytes obtidos do sistema operacional, de runtime.MemStats.Sys.",
+		}),
+		goroutines: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_observer_goroutines",
+			Help: "Número de goroutines em execução.",
+		}),
+		taskQueueDepth: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_observer_task_queue_depth",
+			Help: "Número de tarefas pendentes no TaskManager.",
+		}),
+		errorsTotal: factory.NewCounter(prometheus.CounterOpts{
+			Name: "hivemind_observer_errors_total",
+			Help: "Total de erros observados desde o início do processo.",
+		}),
+		scaleEvents: factory.NewCounter(prometheus.CounterOpts{
+			Name: "hivemind_observer_scale_events_total",
+			Help: "Total de eventos de scale-out disparados por ScaleSystem.",
+		}),
+		storeHits: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "hivemind_observer_memory_store_hits_total",
+			Help: "Total de hits no MemoryStore em camadas, por camada (hot/cold).",
+		}, []string{"tier"}),
+		storeMisses: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "hivemind_observer_memory_store_misses_total",
+			Help: "Total de misses no MemoryStore em camadas, por camada (hot/cold).",
+		}, []string{"tier"}),
+		backendLatency: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "hivemind_observer_backend_latency_seconds",
+			Help:    "Latência das chamadas aos backends de armazenamento, por backend (redis/mongo).",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"backend"}),
+		cpuThreshold: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_observer_cpu_threshold_percent",
+			Help: "Limiar de uso de CPU configurado para disparar scale-out.",
+		}),
+		memoryThreshold: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_observer_memory_threshold_percent",
+			Help: "Limiar de uso de memória configurado para disparar scale-out.",
+		}),
+		tasksThreshold: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_observer_tasks_threshold",
+			Help: "Limiar de tarefas por agente configurado para disparar scale-out.",
+		}),
+		errorThreshold: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_observer_error_threshold",
+			Help: "Limiar de número de erros configurado para disparar scale-out.",
+		}),
+		placementScore: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hivemind_observer_placement_score",
+			Help: "Placement score do host escolhido pelo scheduler para a última instância criada, por tipo de agente e host.",
+		}, []string{"agent_type", "host"}),
+		spreadDeviation: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hivemind_observer_spread_deviation",
+			Help: "Desvio entre o SpreadTarget declarado e a distribuição resultante da última decisão de placement, por tipo de agente e host.",
+		}, []string{"agent_type", "host"}),
+	}
+
+	m.cpuThreshold.Set(CPU_THRESHOLD)
+	m.memoryThreshold.Set(MEMORY_THRESHOLD)
+	m.tasksThreshold.Set(TASKS_THRESHOLD)
+	m.errorThreshold.Set(ERROR_THRESHOLD)
+
+	return m
+}
+
+// ObserveBackendLatency registra a duração de uma chamada a um backend de
+// armazenamento (ex.: "redis", "mongo") no histograma correspondente.
+func (m *ObserverMetrics) ObserveBackendLatency(backend string, d time.Duration) {
+	m.backendLatency.WithLabelValues(backend).Observe(d.Seconds())
+}
+
+// RecordScaleEvent incrementa o contador de eventos de scale-out.
+func (m *ObserverMetrics) RecordScaleEvent() {
+	m.scaleEvents.Inc()
+}
+
+// RecordPlacementDecision publica o placement_score e o spread_deviation do
+// host escolhido pelo scheduler do orquestrador para a última instância
+// criada de agentType.
+func (m *ObserverMetrics) RecordPlacementDecision(agentType, host string, score, deviation float64) {
+	m.placementScore.WithLabelValues(agentType, host).Set(score)
+	m.spreadDeviation.WithLabelValues(agentType, host).Set(deviation)
+}
+
+// recordStoreStats repassa os contadores absolutos de um StoreStats aos
+// CounterVec de hit/miss, somando apenas o delta desde a última chamada.
+func (m *ObserverMetrics) recordStoreStats(hotHits, hotMisses, coldHits, coldMisses int64) {
+	if delta := hotHits - m.lastHotHits; delta > 0 {
+		m.storeHits.WithLabelValues("hot").Add(float64(delta))
+	}
+	if delta := hotMisses - m.lastHotMisses; delta > 0 {
+		m.storeMisses.WithLabelValues("hot").Add(float64(delta))
+	}
+	if delta := coldHits - m.lastColdHits; delta > 0 {
+		m.storeHits.WithLabelValues("cold").Add(float64(delta))
+	}
+	if delta := coldMisses - m.lastColdMisses; delta > 0 {
+		m.storeMisses.WithLabelValues("cold").Add(float64(delta))
+	}
+
+	m.lastHotHits, m.lastHotMisses = hotHits, hotMisses
+	m.lastColdHits, m.lastColdMisses = coldHits, coldMisses
+}
+
+// StartMetricsServer sobe, em uma goroutine, um servidor HTTP expondo /metrics
+// em addr (ex.: ":9090") para o Registerer informado, encerrando quando ctx
+// for cancelado.
+func StartMetricsServer(ctx context.Context, addr string, gatherer prometheus.Gatherer) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("⚠️ erro ao subir servidor de métricas em %s: %v", addr, err)
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// cgroupV2MemoryMaxPath e cgroupV1MemoryLimitPath são os caminhos padrão do
+// limite de memória do cgroup da unified hierarchy (v2) e da hierarchy
+// clássica (v1), respectivamente.
+const (
+	cgroupV2MemoryMaxPath   = "/sys/fs/cgroup/memory.max"
+	cgroupV1MemoryLimitPath = "/sys/fs/cgroup/memory/memory.limit_in_bytes"
+)
+
+// readCgroupMemoryLimit tenta ler o limite de memória do cgroup do processo
+// corrente, primeiro pela unified hierarchy (v2) e depois pela clássica
+// (v1). Retorna 0 quando nenhum dos dois está disponível (ex.: rodando fora
+// de um container) ou quando o limite está configurado como "max"
+// (ilimitado).
+func readCgroupMemoryLimit() uint64 {
+	if limit, ok := readCgroupLimitFile(cgroupV2MemoryMaxPath); ok {
+		return limit
+	}
+	if limit, ok := readCgroupLimitFile(cgroupV1MemoryLimitPath); ok {
+		return limit
+	}
+	return 0
+}
+
+func readCgroupLimitFile(path string) (uint64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, false
+	}
+
+	limit, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return limit, true
+}
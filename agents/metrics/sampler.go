@@ -0,0 +1,154 @@
+// Package metrics amostra métricas reais de host/processo (CPU, RSS,
+// goroutines e limites de cgroup) e contadores por agente (tarefas na fila,
+// tempo de resposta, taxa de erro), registrando tudo num prometheus.Registry
+// compartilhado pelo endpoint HTTP /metrics e pelas decisões de escala do
+// orquestrador — para que ambos enxerguem exatamente os mesmos números.
+package metrics
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Sampler coleta métricas reais de CPU/memória do processo corrente via
+// gopsutil, numa janela deslizante (process.Percent mantém o estado entre
+// chamadas), e as expõe tanto como campos consultáveis diretamente (para
+// ScaleSystem) quanto como séries Prometheus (para o endpoint /metrics).
+type Sampler struct {
+	proc *process.Process
+
+	cpuPercent  prometheus.Gauge
+	rssBytes    prometheus.Gauge
+	goroutines  prometheus.Gauge
+	cgroupLimit prometheus.Gauge
+
+	tasksInQueue *prometheus.GaugeVec
+	responseTime *prometheus.HistogramVec
+	errorRate    *prometheus.CounterVec
+
+	mu      sync.RWMutex
+	lastCPU float64
+	lastRSS uint64
+}
+
+// NewSampler cria um Sampler para o processo corrente, registrando suas
+// séries no Registerer informado. A primeira amostra de CPU tende a ser 0,
+// já que process.Percent mede o intervalo decorrido desde a amostra
+// anterior — chame Sample periodicamente (ex.: no mesmo ticker do
+// ObserverInfrastructureAgent) para uma leitura útil.
+func NewSampler(reg prometheus.Registerer) (*Sampler, error) {
+	proc, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sampler{
+		proc: proc,
+
+		cpuPercent: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_host_cpu_percent",
+			Help: "Uso de CPU do processo, em percentual, amostrado por process.Percent numa janela deslizante.",
+		}),
+		rssBytes: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_host_memory_rss_bytes",
+			Help: "RSS (memória residente) do processo, em bytes.",
+		}),
+		goroutines: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_host_goroutines",
+			Help: "Número de goroutines ativas no processo.",
+		}),
+		cgroupLimit: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "hivemind_host_cgroup_memory_limit_bytes",
+			Help: "Limite de memória do cgroup do processo, quando disponível (0 se ausente, ex.: fora de container).",
+		}),
+		tasksInQueue: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hivemind_agent_tasks_in_queue",
+			Help: "Número de tarefas na fila atribuídas a um agente.",
+		}, []string{"agent"}),
+		responseTime: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "hivemind_agent_response_time_seconds",
+			Help:    "Tempo de resposta de uma tarefa concluída por um agente.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"agent"}),
+		errorRate: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "hivemind_agent_errors_total",
+			Help: "Total de erros reportados por um agente.",
+		}, []string{"agent"}),
+	}, nil
+}
+
+// Sample atualiza as séries de host (CPU, RSS, goroutines, limite de
+// cgroup) com uma nova leitura. Deve ser chamado periodicamente pelo mesmo
+// ticker que já aciona o resto da coleta de métricas.
+func (s *Sampler) Sample(ctx context.Context) error {
+	cpuPercent, err := s.proc.PercentWithContext(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	memInfo, err := s.proc.MemoryInfoWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.lastCPU = cpuPercent
+	s.lastRSS = memInfo.RSS
+	s.mu.Unlock()
+
+	s.cpuPercent.Set(cpuPercent)
+	s.rssBytes.Set(float64(memInfo.RSS))
+	s.goroutines.Set(float64(runtime.NumGoroutine()))
+
+	if limit := readCgroupMemoryLimit(); limit > 0 {
+		s.cgroupLimit.Set(float64(limit))
+	}
+
+	return nil
+}
+
+// CPUPercent retorna o uso de CPU, em percentual, da última chamada a
+// Sample.
+func (s *Sampler) CPUPercent() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastCPU
+}
+
+// RSSBytes retorna a memória residente, em bytes, da última chamada a
+// Sample.
+func (s *Sampler) RSSBytes() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastRSS
+}
+
+// RecordTasksInQueue atualiza o número de tarefas na fila de um agente
+// específico.
+func (s *Sampler) RecordTasksInQueue(agentName string, count int) {
+	s.tasksInQueue.WithLabelValues(agentName).Set(float64(count))
+}
+
+// ObserveResponseTime registra o tempo de resposta de uma tarefa concluída
+// por um agente.
+func (s *Sampler) ObserveResponseTime(agentName string, d time.Duration) {
+	s.responseTime.WithLabelValues(agentName).Observe(d.Seconds())
+}
+
+// RecordError incrementa o contador de erros de um agente.
+func (s *Sampler) RecordError(agentName string) {
+	s.errorRate.WithLabelValues(agentName).Inc()
+}
+
+// CgroupMemoryLimitBytes retorna o limite de memória do cgroup do processo
+// corrente, ou 0 se não estiver rodando sob um cgroup com limite configurado.
+func (s *Sampler) CgroupMemoryLimitBytes() uint64 {
+	return readCgroupMemoryLimit()
+}
@@ -1,22 +1,55 @@
 package agents
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"runtime"
 	"sync"
 	"time"
+
+	"HiveMindForge/agents/agentrpc"
+	"HiveMindForge/agents/memory"
+	"HiveMindForge/agents/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
 )
 
-// ObserverInfrastructureAgent monitora o estado do sistema e coleta métricas
+// ObserverInfrastructureAgent monitora o estado do sistema e coleta métricas,
+// tanto no SystemMetrics interno consultado por ScaleSystem quanto, agora, em
+// séries Prometheus raspáveis por um stack externo de observabilidade. Embute
+// agentrpc.Server para expor PushMetrics aos CognitiveAgents via o shim de
+// agentrpc, sobrescrevendo apenas esse método e deixando os demais caírem no
+// stub "não implementado" por promoção (os outros três pertencem ao
+// OrchestratorInfrastructureAgent).
 type ObserverInfrastructureAgent struct {
 	Agent
+	agentrpc.Server
 	metrics     *SystemMetrics
 	metricsLock sync.RWMutex
 	stopChan    chan struct{}
+
+	prom    *ObserverMetrics
+	sampler *metrics.Sampler
+
+	// taskManager e storeStats são plugados via setter pelo orquestrador após
+	// a construção, já que não existem ainda quando o observador é criado.
+	taskManagerLock sync.RWMutex
+	taskManager     *TaskManager
+	storeStats      func() memory.StoreStats
 }
 
-// NewObserverInfrastructureAgent cria uma nova instância do agente observador
-func NewObserverInfrastructureAgent() *ObserverInfrastructureAgent {
+// NewObserverInfrastructureAgent cria uma nova instância do agente observador,
+// registrando suas métricas Prometheus no Registerer informado e, se addr não
+// for vazio, subindo um servidor HTTP expondo /metrics nesse endereço. O
+// sampler de métricas de host (CPU/RSS/cgroup) é construído aqui também, já
+// que ambos compartilham o mesmo Registerer.
+func NewObserverInfrastructureAgent(ctx context.Context, reg prometheus.Registerer, addr string) (*ObserverInfrastructureAgent, error) {
+	sampler, err := metrics.NewSampler(reg)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar sampler de métricas de host: %v", err)
+	}
+
 	agent := &ObserverInfrastructureAgent{
 		Agent: Agent{
 			Name:            "System Observer",
@@ -28,10 +61,54 @@ func NewObserverInfrastructureAgent() *ObserverInfrastructureAgent {
 		},
 		metrics:  &SystemMetrics{},
 		stopChan: make(chan struct{}),
+		prom:     NewObserverMetrics(reg),
+		sampler:  sampler,
+	}
+
+	if addr != "" {
+		if gatherer, ok := reg.(prometheus.Gatherer); ok {
+			StartMetricsServer(ctx, addr, gatherer)
+		} else {
+			StartMetricsServer(ctx, addr, prometheus.DefaultGatherer)
+		}
 	}
 
 	go agent.startMonitoring()
-	return agent
+	return agent, nil
+}
+
+// SetTaskManager associa o TaskManager cujo tamanho da fila de tarefas
+// pendentes será exportado como hivemind_observer_task_queue_depth.
+func (o *ObserverInfrastructureAgent) SetTaskManager(tm *TaskManager) {
+	o.taskManagerLock.Lock()
+	defer o.taskManagerLock.Unlock()
+	o.taskManager = tm
+}
+
+// SetStoreStatsProvider associa uma função que devolve o StoreStats atual do
+// TieredStore de memória, para que seus contadores de hit/miss sejam
+// refletidos nas métricas Prometheus a cada coleta.
+func (o *ObserverInfrastructureAgent) SetStoreStatsProvider(f func() memory.StoreStats) {
+	o.taskManagerLock.Lock()
+	defer o.taskManagerLock.Unlock()
+	o.storeStats = f
+}
+
+// RecordScaleEvent repassa um evento de scale-out para as métricas Prometheus.
+func (o *ObserverInfrastructureAgent) RecordScaleEvent() {
+	o.prom.RecordScaleEvent()
+}
+
+// RecordPlacementDecision repassa a decisão de placement do scheduler do
+// orquestrador para as métricas Prometheus placement_score/spread_deviation.
+func (o *ObserverInfrastructureAgent) RecordPlacementDecision(agentType, host string, score, deviation float64) {
+	o.prom.RecordPlacementDecision(agentType, host, score, deviation)
+}
+
+// ObserveBackendLatency repassa a latência de uma chamada a um backend de
+// armazenamento (ex.: "redis", "mongo") para o histograma Prometheus.
+func (o *ObserverInfrastructureAgent) ObserveBackendLatency(backend string, d time.Duration) {
+	o.prom.ObserveBackendLatency(backend, d)
 }
 
 // startMonitoring inicia a coleta de métricas
@@ -49,28 +126,111 @@ func (o *ObserverInfrastructureAgent) startMonitoring() {
 	}
 }
 
-// collectMetrics coleta métricas do sistema
+// collectMetrics coleta métricas do sistema e as publica tanto no
+// SystemMetrics interno (consultado por ScaleSystem) quanto nas séries
+// Prometheus registradas em o.prom.
 func (o *ObserverInfrastructureAgent) collectMetrics() {
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
 
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := o.sampler.Sample(ctx); err != nil {
+		log.Printf("⚠️ falha ao amostrar métricas de host: %v", err)
+	}
+
 	o.metricsLock.Lock()
 	defer o.metricsLock.Unlock()
 
-	// Calcular uso de CPU (exemplo simplificado)
-	cpuUsage := float64(runtime.NumGoroutine()) / float64(runtime.NumCPU()) * 100
+	// Uso real de CPU do processo, medido via gopsutil.
+	cpuUsage := o.sampler.CPUPercent()
 
-	// Calcular uso de memória
+	// Uso de memória: se o processo está sob um cgroup com limite conhecido,
+	// usar RSS/limite; caso contrário, cair de volta na razão de heap do Go.
 	memoryUsage := float64(m.Alloc) / float64(m.Sys) * 100
+	if limit := o.sampler.CgroupMemoryLimitBytes(); limit > 0 {
+		memoryUsage = float64(o.sampler.RSSBytes()) / float64(limit) * 100
+	}
 
 	o.metrics.CPUUsage = cpuUsage
 	o.metrics.MemoryUsage = memoryUsage
 	o.metrics.LastUpdate = time.Now().Unix()
 
+	o.prom.cpuUsage.Set(cpuUsage)
+	o.prom.memoryUsage.Set(memoryUsage)
+	o.prom.memoryAlloc.Set(float64(m.Alloc))
+	o.prom.memorySys.Set(float64(m.Sys))
+	o.prom.goroutines.Set(float64(runtime.NumGoroutine()))
+
+	o.taskManagerLock.RLock()
+	taskManager, storeStats := o.taskManager, o.storeStats
+	o.taskManagerLock.RUnlock()
+
+	if taskManager != nil {
+		depth := taskManager.QueueDepth()
+		o.metrics.TasksPerAgent = depth
+		o.prom.taskQueueDepth.Set(float64(depth))
+	}
+
+	if storeStats != nil {
+		stats := storeStats()
+		o.prom.recordStoreStats(stats.HotHits, stats.HotMisses, stats.ColdHits, stats.ColdMisses)
+	}
+
 	log.Printf("📊 Métricas atualizadas - CPU: %.2f%%, Memória: %.2f%%",
 		cpuUsage, memoryUsage)
 }
 
+// RecordError incrementa o contador de erros exposto em
+// hivemind_observer_errors_total, além do contador interno de ErrorCount.
+func (o *ObserverInfrastructureAgent) RecordError() {
+	o.metricsLock.Lock()
+	o.metrics.ErrorCount++
+	o.metricsLock.Unlock()
+
+	o.prom.errorsTotal.Inc()
+}
+
+// RecordTasksInQueue atualiza, para um agente específico, o número de
+// tarefas na fila exposto em hivemind_agent_tasks_in_queue.
+func (o *ObserverInfrastructureAgent) RecordTasksInQueue(agentName string, count int) {
+	o.sampler.RecordTasksInQueue(agentName, count)
+}
+
+// ObserveAgentResponseTime registra, para um agente específico, o tempo de
+// resposta de uma tarefa concluída.
+func (o *ObserverInfrastructureAgent) ObserveAgentResponseTime(agentName string, d time.Duration) {
+	o.sampler.ObserveResponseTime(agentName, d)
+}
+
+// RecordAgentError incrementa, para um agente específico, o contador de
+// erros exposto em hivemind_agent_errors_total.
+func (o *ObserverInfrastructureAgent) RecordAgentError(agentName string) {
+	o.sampler.RecordError(agentName)
+}
+
+// PushMetrics implementa agentrpc.AgentAPIServer, substituindo a antiga fila
+// ad-hoc "metrics.<agente>.<métrica>" por uma chamada tipada: cada
+// MetricSample é roteada para a série Prometheus por agente correspondente
+// ao seu Name ("tasks_in_queue", "response_time_seconds" ou "error"), via
+// RecordTasksInQueue, ObserveAgentResponseTime e RecordAgentError.
+func (o *ObserverInfrastructureAgent) PushMetrics(ctx context.Context, req *agentrpc.PushMetricsRequest) (*agentrpc.PushMetricsResponse, error) {
+	for _, sample := range req.Samples {
+		switch sample.Name {
+		case "tasks_in_queue":
+			o.RecordTasksInQueue(req.AgentID, int(sample.Value))
+		case "response_time_seconds":
+			o.ObserveAgentResponseTime(req.AgentID, time.Duration(sample.Value*float64(time.Second)))
+		case "error":
+			o.RecordAgentError(req.AgentID)
+		default:
+			log.Printf("⚠️ agentrpc: amostra de métrica desconhecida %q do agente %s", sample.Name, req.AgentID)
+		}
+	}
+
+	return &agentrpc.PushMetricsResponse{}, nil
+}
+
 // GetSystemMetrics retorna as métricas atuais do sistema
 func (o *ObserverInfrastructureAgent) GetSystemMetrics() *SystemMetrics {
 	o.metricsLock.RLock()
@@ -34,11 +34,26 @@ func (o *OrchestratorAgent) AssignCognitiveAgents(agents ...*CognitiveAgent) {
 	o.CognitiveAgents = agents
 }
 
-// DelegateTask delega uma tarefa para um agente cognitivo específico
+// DelegateTask delega uma tarefa para um agente cognitivo específico. O
+// resultado é gravado em task.ResultWriter() além de retornado diretamente,
+// para que TaskManager.GetTaskResult também o enxergue sem que este método
+// precise saber como o resultado é persistido.
 func (o *OrchestratorAgent) DelegateTask(agent *CognitiveAgent, task *Task) (string, error) {
 	log.Printf("🎯 Delegando tarefa '%s' para o agente %s", task.Description, agent.Name)
 
-	o.HiveMind.AssignAgents(&agent.Agent)
+	o.HiveMind.AssignAgents(&Agent{
+		ID:               agent.ID,
+		Name:             agent.Name,
+		Role:             agent.Role,
+		Goal:             agent.Goal,
+		AllowDelegation:  agent.AllowDelegation,
+		Model:            agent.Model,
+		Backstory:        agent.Backstory,
+		Temperature:      agent.Temperature,
+		MaxTokens:        agent.MaxTokens,
+		ContextWindow:    agent.ContextWindow,
+		PerformanceStats: agent.PerformanceStats,
+	})
 	o.HiveMind.AssignTasks(task)
 
 	result, err := o.HiveMind.Execute()
@@ -46,6 +61,10 @@ func (o *OrchestratorAgent) DelegateTask(agent *CognitiveAgent, task *Task) (str
 		return "", fmt.Errorf("erro ao executar tarefa: %v", err)
 	}
 
+	if _, err := task.ResultWriter().Write([]byte(result)); err != nil {
+		return "", fmt.Errorf("erro ao gravar resultado da tarefa: %v", err)
+	}
+
 	return result, nil
 }
 
@@ -1,29 +1,124 @@
 package agents
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
 	"sync"
 	"time"
+
+	"HiveMindForge/agents/agentrpc"
+	"HiveMindForge/agents/hashring"
+	"HiveMindForge/agents/idents"
+	"HiveMindForge/agents/memory"
+	"HiveMindForge/agents/scheduler"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
-// OrchestratorInfrastructureAgent gerencia a infraestrutura e escalabilidade do sistema
+// replicaHeartbeatRole marca, no mesmo keyspace `ident:*` usado pelos
+// CognitiveAgents, os heartbeats publicados por réplicas do próprio
+// OrchestratorInfrastructureAgent — permitindo que watchRingMembership
+// distinga réplicas de agentes comuns ao popular o ring.
+const replicaHeartbeatRole = "orchestrator-replica"
+
+// OrchestratorInfrastructureAgent gerencia a infraestrutura e escalabilidade
+// do sistema. Embute agentrpc.Server para satisfazer agentrpc.AgentAPIServer
+// por padrão e sobrescreve todos os quatro métodos: PushMetrics delega ao
+// ObserverInfrastructureAgent desta réplica, os demais usam taskManager e
+// ring diretamente. É o Orchestrator quem roda agentrpc.Serve, já que só ele
+// tem acesso ao Broker.
 type OrchestratorInfrastructureAgent struct {
 	Agent
-	taskManager   *TaskManager
-	agents        map[string]*CognitiveAgent
-	agentLock     sync.RWMutex
-	lastScaleTime time.Time
-	observerAgent *ObserverInfrastructureAgent
+	agentrpc.Server
+	broker          Broker
+	taskManager     *TaskManager
+	targetCache     *idents.TargetCache
+	ring            *hashring.HashRing // membros são réplicas do orquestrador, não agentes
+	replicaID       string
+	replicaStopChan chan struct{}
+	agentLock       sync.RWMutex
+	agents          map[string]*CognitiveAgent // agentes locais, clonados via scaleOut
+	lastScaleTime   time.Time
+	observerAgent   *ObserverInfrastructureAgent
+
+	// memoryManager é o HybridMemoryManager compartilhado por esta réplica,
+	// particionado pelo mesmo hashring usado para tarefas (SetSharder) e com
+	// sua goroutine de reaping de leases (StartLeaseGC) já em execução.
+	memoryManager *memory.HybridMemoryManager
+
+	// scheduler materializa e despacha as Task de ScheduledTask registradas
+	// nesta réplica; já iniciado pelo construtor, mas vazio até que algum
+	// chamador use GetScheduler().Schedule(...).
+	scheduler *Scheduler
+
+	// scorer rankeia os hosts candidatos do TargetCache ao posicionar cada
+	// novo clone em scaleOut; AffinitySpreadScorer é o padrão, mas pode ser
+	// trocado (ex.: por scheduler.BinPackScorer) via SetScorer.
+	scorer scheduler.Scorer
+}
+
+// newReplicaID gera um identificador razoavelmente único para esta réplica
+// do orquestrador, a partir do hostname e do PID — suficiente para
+// distingui-la de outras réplicas no hashring sem exigir coordenação externa.
+func newReplicaID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown-host"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
 }
 
 // NewOrchestratorInfrastructureAgent cria uma nova instância do agente orquestrador de infraestrutura
-func NewOrchestratorInfrastructureAgent() (*OrchestratorInfrastructureAgent, error) {
-	taskManager, err := NewTaskManager()
+func NewOrchestratorInfrastructureAgent(ctx context.Context) (*OrchestratorInfrastructureAgent, error) {
+	broker, err := NewAMQPBroker(DefaultBrokerConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	taskManager, err := NewTaskManager(broker, prometheus.DefaultRegisterer)
+	if err != nil {
+		return nil, err
+	}
+
+	observer, err := NewObserverInfrastructureAgent(ctx, prometheus.DefaultRegisterer, METRICS_ADDR)
 	if err != nil {
 		return nil, err
 	}
+	observer.SetTaskManager(taskManager)
 
-	observer := NewObserverInfrastructureAgent()
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: fmt.Sprintf("%s:%d", REDIS_HOST, REDIS_PORT),
+	})
+
+	targetCache := idents.NewTargetCache(redisClient, DEFAULT_HEARTBEAT_TICK*time.Second)
+	targetCache.Start(ctx)
+
+	replicaID := newReplicaID()
+
+	// A réplica se inclui no ring imediatamente, sem esperar que seu próprio
+	// heartbeat dê a volta pelo Redis e pelo TargetCache — assim OwnsAgent já
+	// funciona corretamente a partir do primeiro ScaleSystem.
+	ring := hashring.NewHashRing(hashring.DefaultVirtualNodes)
+	ring.Add(replicaID)
+	taskManager.SetHashRing(ring, replicaID)
+
+	memoryManager, err := memory.NewHybridMemoryManager(ctx, memory.DefaultMemoryConfig())
+	if err != nil {
+		return nil, err
+	}
+	memoryManager.SetSharder(ring, replicaID)
+
+	sched := NewScheduler(taskManager, nil)
+	sched.Start()
+
+	replicaStopChan := make(chan struct{})
 
 	agent := &OrchestratorInfrastructureAgent{
 		Agent: Agent{
@@ -34,44 +129,271 @@ func NewOrchestratorInfrastructureAgent() (*OrchestratorInfrastructureAgent, err
 			Model:           "gpt-4",
 			Backstory:       "Um agente especializado em gerenciar e otimizar a infraestrutura do sistema",
 		},
-		taskManager:   taskManager,
-		agents:        make(map[string]*CognitiveAgent),
-		lastScaleTime: time.Now(),
-		observerAgent: observer,
+		broker:          broker,
+		taskManager:     taskManager,
+		targetCache:     targetCache,
+		ring:            ring,
+		replicaID:       replicaID,
+		replicaStopChan: replicaStopChan,
+		agents:          make(map[string]*CognitiveAgent),
+		lastScaleTime:   time.Now(),
+		observerAgent:   observer,
+		scorer:          scheduler.AffinitySpreadScorer{},
+		memoryManager:   memoryManager,
+		scheduler:       sched,
 	}
 
+	idents.StartPublishing(ctx, redisClient, DEFAULT_HEARTBEAT_TICK*time.Second, replicaStopChan, func() *idents.Heartbeat {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+
+		metrics := observer.GetSystemMetrics()
+
+		return &idents.Heartbeat{
+			AgentID:       replicaID,
+			Role:          replicaHeartbeatRole,
+			Version:       agent.Model,
+			MemoryUsage:   mem.Alloc,
+			InFlightTasks: taskManager.QueueDepth(),
+			ErrorCount:    metrics.ErrorCount,
+		}
+	})
+
+	go agent.watchRingMembership(ctx)
+
+	// Réplicas que caem sem renovar suas leases não podem mais travar a
+	// consolidação/poda das memórias que deixaram presas: qualquer dono cujo
+	// heartbeat tenha saído do TargetCache é considerado morto para fins de
+	// reaping.
+	memory.StartLeaseGC(ctx, memoryManager, DEFAULT_HEARTBEAT_TICK*time.Second, func(agentID string) bool {
+		_, alive := targetCache.Get(agentID)
+		return alive
+	})
+
+	go func() {
+		if err := agentrpc.Serve(ctx, broker, EXCHANGE_AGENT_RPC, agent); err != nil && ctx.Err() == nil {
+			log.Printf("⚠️ agentrpc: servidor de RPC de agente encerrou: %v", err)
+		}
+	}()
+
 	return agent, nil
 }
 
-// RegisterAgent registra um novo agente cognitivo
-func (o *OrchestratorInfrastructureAgent) RegisterAgent(agent *CognitiveAgent) {
+// watchRingMembership reconstrói o hashring de réplicas sempre que o
+// TargetCache emite um evento de join/leave, mantendo o roteamento de
+// tarefas e a propriedade de agentes (OwnsAgent) consistente com as réplicas
+// do orquestrador realmente vivas. Heartbeats de CognitiveAgents comuns são
+// ignorados aqui: eles compartilham o mesmo keyspace `ident:*`, mas só
+// réplicas (Role == replicaHeartbeatRole) são membros deste ring.
+func (o *OrchestratorInfrastructureAgent) watchRingMembership(ctx context.Context) {
+	events := o.targetCache.Watch()
+
+	for _, hb := range o.targetCache.All() {
+		if hb.Role == replicaHeartbeatRole {
+			o.ring.Add(hb.AgentID)
+		}
+	}
+
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if ev.Heartbeat.Role != replicaHeartbeatRole {
+				continue
+			}
+			switch ev.Type {
+			case idents.EventJoin:
+				o.ring.Add(ev.Heartbeat.AgentID)
+				log.Printf("🔗 hashring: réplica %s entrou", ev.Heartbeat.AgentID)
+			case idents.EventLeave:
+				o.ring.Remove(ev.Heartbeat.AgentID)
+				log.Printf("🔗 hashring: réplica %s saiu", ev.Heartbeat.AgentID)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// GetHashRing retorna o hashring usado para decidir a réplica dona de uma
+// tarefa ou chave de memória.
+func (o *OrchestratorInfrastructureAgent) GetHashRing() *hashring.HashRing {
+	return o.ring
+}
+
+// GetMemoryManager retorna o HybridMemoryManager desta réplica, já
+// particionado pelo hashring (SetSharder) e com a goroutine de reaping de
+// leases (StartLeaseGC) em execução, para uso por CognitiveAgents
+// registrados nesta réplica.
+func (o *OrchestratorInfrastructureAgent) GetMemoryManager() *memory.HybridMemoryManager {
+	return o.memoryManager
+}
+
+// OwnsAgent indica se esta réplica é a dona, segundo o hashring, do agente
+// identificado por agentID. Antes de qualquer réplica ter entrado no ring
+// (ring.OwnerOf retornando ok=false), assume-se posse para não travar
+// ScaleSystem num cluster ainda sem heartbeats de réplica propagados.
+func (o *OrchestratorInfrastructureAgent) OwnsAgent(agentID string) bool {
+	owner, ok := o.ring.OwnerOf(agentID)
+	if !ok {
+		return true
+	}
+	return owner == o.replicaID
+}
+
+// RegisterAgent registra um novo agente cognitivo localmente e dispara seu
+// heartbeat, para que ele também apareça no TargetCache compartilhado.
+func (o *OrchestratorInfrastructureAgent) RegisterAgent(ctx context.Context, agent *CognitiveAgent) {
 	o.agentLock.Lock()
 	defer o.agentLock.Unlock()
 
 	o.agents[agent.Name] = agent
+
+	if err := agent.RegisterHeartbeat(ctx, DEFAULT_HEARTBEAT_TICK*time.Second); err != nil {
+		log.Printf("⚠️ falha ao registrar heartbeat do agente %s: %v", agent.Name, err)
+	}
+
+	agent.SetTaskManager(o.taskManager)
+
+	if client, err := agentrpc.NewClient(ctx, o.broker, EXCHANGE_AGENT_RPC, agent.ID); err != nil {
+		log.Printf("⚠️ falha ao criar cliente agentrpc para %s: %v", agent.Name, err)
+	} else {
+		agent.SetAgentRPCClient(client)
+	}
+
+	agent.RegisterHealthCheck(ctx, NewGroqReachabilityProbe(30*time.Second, 3))
+	agent.RegisterHealthCheck(ctx, NewRabbitMQLivenessProbe(o.taskManager, 15*time.Second, 3))
+	agent.StartHealthReporting(ctx, DEFAULT_HEARTBEAT_TICK*time.Second)
+
 	log.Printf("✨ Agente registrado: %s", agent.Name)
 }
 
+// SetScorer troca o scheduler.Scorer usado por scaleOut ao posicionar novos
+// clones, permitindo substituir o AffinitySpreadScorer padrão por uma
+// estratégia diferente (ex.: scheduler.BinPackScorer) por tipo de deploy.
+func (o *OrchestratorInfrastructureAgent) SetScorer(scorer scheduler.Scorer) {
+	o.agentLock.Lock()
+	defer o.agentLock.Unlock()
+	o.scorer = scorer
+}
+
 // GetTaskManager retorna o gerenciador de tarefas
 func (o *OrchestratorInfrastructureAgent) GetTaskManager() *TaskManager {
 	return o.taskManager
 }
 
-// AddTask adiciona uma nova tarefa ao sistema
-func (o *OrchestratorInfrastructureAgent) AddTask(task *Task) {
-	o.taskManager.AddTask(task)
+// GetTargetCache retorna o cache de agentes vivos, alimentado pelos
+// heartbeats publicados no Redis.
+func (o *OrchestratorInfrastructureAgent) GetTargetCache() *idents.TargetCache {
+	return o.targetCache
+}
+
+// GetScheduler retorna o Scheduler desta réplica, já em execução, para que
+// chamadores registrem ScheduledTask via Schedule.
+func (o *OrchestratorInfrastructureAgent) GetScheduler() *Scheduler {
+	return o.scheduler
+}
+
+// AddTask adiciona uma nova tarefa ao sistema, repassando o contrato de
+// idempotência de TaskManager.AddTask (ErrTaskIDConflict em retries com o
+// mesmo task.ID).
+func (o *OrchestratorInfrastructureAgent) AddTask(task *Task) (*Task, error) {
+	return o.taskManager.AddTask(task)
+}
+
+// PushMetrics implementa agentrpc.AgentAPIServer delegando ao
+// ObserverInfrastructureAgent desta réplica, já que é ele quem possui as
+// séries Prometheus por agente — Serve é iniciado apenas aqui, então o
+// Orchestrator precisa cobrir os quatro métodos de AgentAPIServer.
+func (o *OrchestratorInfrastructureAgent) PushMetrics(ctx context.Context, req *agentrpc.PushMetricsRequest) (*agentrpc.PushMetricsResponse, error) {
+	return o.observerAgent.PushMetrics(ctx, req)
+}
+
+// ReportHealth implementa agentrpc.AgentAPIServer, substituindo a publicação
+// manual de AgentHealth em EXCHANGE_HEALTH por uma chamada tipada que acaba
+// no mesmo TaskManager.EmitHealthSignal de sempre.
+func (o *OrchestratorInfrastructureAgent) ReportHealth(ctx context.Context, req *agentrpc.ReportHealthRequest) (*agentrpc.ReportHealthResponse, error) {
+	health := &AgentHealth{
+		AgentName:      req.AgentID,
+		IsProcessing:   req.IsProcessing,
+		ProcessingTime: req.ProcessingTime,
+		LastHeartbeat:  time.Now(),
+		ProbeState:     ProbeState(req.ProbeState),
+	}
+
+	if err := o.taskManager.EmitHealthSignal(health); err != nil {
+		return nil, fmt.Errorf("erro ao emitir sinal de saúde via agentrpc: %v", err)
+	}
+
+	return &agentrpc.ReportHealthResponse{}, nil
+}
+
+// PullTasks implementa agentrpc.AgentAPIServer sobre TaskManager.GetNextTask,
+// que devolve no máximo uma tarefa por chamada — req.MaxTasks acima de 1 não
+// amplia isso, só documenta quantas o agente chamador conseguiria processar.
+func (o *OrchestratorInfrastructureAgent) PullTasks(ctx context.Context, req *agentrpc.PullTasksRequest) (*agentrpc.PullTasksResponse, error) {
+	task := o.taskManager.GetNextTask(req.AgentID)
+	if task == nil {
+		return &agentrpc.PullTasksResponse{}, nil
+	}
+
+	data, err := json.Marshal(task.Data)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao codificar dados da tarefa via agentrpc: %v", err)
+	}
+
+	return &agentrpc.PullTasksResponse{
+		Tasks: []agentrpc.Task{{
+			ID:       task.ID,
+			Type:     task.Type,
+			Priority: int32(task.Priority),
+			Data:     data,
+		}},
+	}, nil
+}
+
+// RequestScale implementa agentrpc.AgentAPIServer, permitindo que um agente
+// peça uma reavaliação de escala fora do ciclo normal de ScaleSystem. Ignora
+// pedidos para agentes que não são desta réplica segundo o hashring.
+func (o *OrchestratorInfrastructureAgent) RequestScale(ctx context.Context, req *agentrpc.RequestScaleRequest) (*agentrpc.RequestScaleResponse, error) {
+	if !o.OwnsAgent(req.AgentID) {
+		return &agentrpc.RequestScaleResponse{Scaled: false}, nil
+	}
+
+	wasScaling := o.CheckScaling()
+	if wasScaling {
+		log.Printf("📈 agentrpc: escala solicitada por %s (motivo: %s)", req.AgentID, req.Reason)
+	}
+
+	lastScale := o.lastScaleTime
+	o.ScaleSystem(ctx)
+
+	return &agentrpc.RequestScaleResponse{Scaled: o.lastScaleTime.After(lastScale)}, nil
 }
 
 // ScaleSystem avalia e ajusta a escala do sistema
-func (o *OrchestratorInfrastructureAgent) ScaleSystem() {
+func (o *OrchestratorInfrastructureAgent) ScaleSystem(ctx context.Context) {
 	// Verificar cooldown de escala
 	if time.Since(o.lastScaleTime) < SCALE_COOLDOWN {
 		return
 	}
 
+	if o.CheckScaling() {
+		o.scaleOut(ctx)
+		o.observerAgent.RecordScaleEvent()
+		o.lastScaleTime = time.Now()
+	}
+}
+
+// CheckScaling avalia as métricas atuais do observador — as mesmas números
+// que o endpoint Prometheus /metrics expõe, já que ambos são lidos do mesmo
+// ObserverInfrastructureAgent — e retorna true se alguma delas ultrapassa seu
+// respectivo limiar, indicando que o sistema precisa de mais capacidade.
+func (o *OrchestratorInfrastructureAgent) CheckScaling() bool {
 	metrics := o.observerAgent.GetSystemMetrics()
 
-	// Verificar condições para escala
 	needsScaling := false
 
 	if metrics.CPUUsage > CPU_THRESHOLD {
@@ -94,39 +416,150 @@ func (o *OrchestratorInfrastructureAgent) ScaleSystem() {
 		needsScaling = true
 	}
 
-	if needsScaling {
-		o.scaleOut()
-		o.lastScaleTime = time.Now()
-	}
+	return needsScaling
 }
 
-// scaleOut aumenta a capacidade do sistema
-func (o *OrchestratorInfrastructureAgent) scaleOut() {
+// scaleOut aumenta a capacidade do sistema, agora consultando o snapshot do
+// TargetCache em vez do mapa local, de modo que agentes rodando em outros
+// processos/pods também sejam considerados para escala. Cada réplica do
+// orquestrador só age sobre os agentes dos quais é dona segundo o hashring
+// (OwnsAgent), evitando que múltiplas réplicas clonem o mesmo agente
+// sobrecarregado simultaneamente.
+func (o *OrchestratorInfrastructureAgent) scaleOut(ctx context.Context) {
 	o.agentLock.RLock()
-	defer o.agentLock.RUnlock()
+	targets := o.targetCache.All()
+	o.agentLock.RUnlock()
 
-	// Identificar agentes mais sobrecarregados
-	for _, agent := range o.agents {
-		health := o.taskManager.GetAgentHealth(agent.Name)
+	for _, target := range targets {
+		if !o.OwnsAgent(target.AgentID) {
+			continue
+		}
+
+		health := o.taskManager.GetAgentHealth(target.AgentID)
 		if health == nil {
 			continue
 		}
 
 		// Se o agente está sobrecarregado, criar um clone
 		if health.IsProcessing && health.ProcessingTime > 5.0 { // mais de 5 segundos por tarefa
-			newAgent := agent.Clone()
-			o.RegisterAgent(newAgent)
+			o.agentLock.RLock()
+			existing, ok := o.agents[target.AgentID]
+			o.agentLock.RUnlock()
+			if !ok {
+				continue
+			}
+
+			newAgent := existing.Clone()
+			o.placeInstance(newAgent, targets)
+			o.RegisterAgent(ctx, newAgent)
 			log.Printf("🔄 Novo agente criado: %s", newAgent.Name)
 		}
 	}
 }
 
+// placeInstance escolhe, entre os hosts vivos no TargetCache, onde newAgent
+// deve rodar, segundo o scorer configurado nesta réplica. candidates vêm dos
+// heartbeats de targets; placed reúne apenas os heartbeats já associados a
+// agentes locais do mesmo tipo (mesmo Name de newAgent), para que a penalidade
+// de spread seja calculada sobre a distribuição real desse tipo de agente, não
+// do cluster inteiro. Se nenhum candidato tiver atributos publicados, a nova
+// instância segue sem uma decisão de placement.
+func (o *OrchestratorInfrastructureAgent) placeInstance(newAgent *CognitiveAgent, targets []*idents.Heartbeat) {
+	candidates := make([]scheduler.Candidate, 0, len(targets))
+	placed := make([]scheduler.Candidate, 0, len(targets))
+
+	o.agentLock.RLock()
+	defer o.agentLock.RUnlock()
+
+	for _, target := range targets {
+		candidate := heartbeatToCandidate(target)
+		candidates = append(candidates, candidate)
+
+		if local, ok := o.agents[target.AgentID]; ok && local.Name == newAgent.Name {
+			placed = append(placed, candidate)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return
+	}
+
+	decisions := o.scorer.Score(candidates, placed, newAgent.PlacementConstraints, newAgent.SpreadAttribute, newAgent.SpreadTarget)
+	if len(decisions) == 0 {
+		return
+	}
+
+	best := decisions[0]
+	newAgent.Attributes = best.Candidate.Attributes
+
+	o.observerAgent.RecordPlacementDecision(newAgent.Name, best.Candidate.Host, best.PlacementScore, best.SpreadDeviation)
+	log.Printf("📍 placement: %s -> host %s (score=%.2f, spread_deviation=%.2f)",
+		newAgent.Name, best.Candidate.Host, best.PlacementScore, best.SpreadDeviation)
+}
+
+// heartbeatToCandidate converte um Heartbeat do TargetCache em um
+// scheduler.Candidate, usando o AgentID como host e anexando
+// "in_flight_tasks" aos Attributes publicados, para que BinPackScorer possa
+// ler a carga atual do candidato sem depender de um campo dedicado em
+// scheduler.Candidate.
+func heartbeatToCandidate(hb *idents.Heartbeat) scheduler.Candidate {
+	attrs := make(map[string]string, len(hb.Attributes)+1)
+	for k, v := range hb.Attributes {
+		attrs[k] = v
+	}
+	attrs["in_flight_tasks"] = strconv.Itoa(hb.InFlightTasks)
+
+	return scheduler.Candidate{
+		Host:       hb.AgentID,
+		Attributes: attrs,
+	}
+}
+
+// ServeCheckin expõe um endpoint HTTP para que agentes externos registrem
+// manualmente seu heartbeat, útil quando o agente não consegue falar
+// diretamente com o Redis compartilhado (ex.: atrás de um proxy).
+func (o *OrchestratorInfrastructureAgent) ServeCheckin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "método não permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var hb idents.Heartbeat
+	if err := json.NewDecoder(r.Body).Decode(&hb); err != nil {
+		http.Error(w, "corpo da requisição inválido", http.StatusBadRequest)
+		return
+	}
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: fmt.Sprintf("%s:%d", REDIS_HOST, REDIS_PORT),
+	})
+	defer redisClient.Close()
+
+	if err := idents.Publish(r.Context(), redisClient, &hb, DEFAULT_HEARTBEAT_TICK*time.Second); err != nil {
+		http.Error(w, "falha ao publicar heartbeat", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // Stop interrompe o agente orquestrador
 func (o *OrchestratorInfrastructureAgent) Stop() {
 	o.agentLock.Lock()
 	defer o.agentLock.Unlock()
 
+	close(o.replicaStopChan)
+	o.targetCache.Stop()
+	o.scheduler.Stop()
+
 	for _, agent := range o.agents {
 		agent.Stop()
 	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := o.taskManager.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⚠️ falha ao desligar o task manager de forma graciosa: %v", err)
+	}
 }
@@ -0,0 +1,91 @@
+package agents
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// StageProgress resume o progresso de uma Task de múltiplas fases, devolvido
+// por AgentReporter.ReportStage para que clientes renderizem progresso
+// estilo Gantt sem precisar inspecionar Task.Stages diretamente.
+type StageProgress struct {
+	TaskID    string `json:"task_id"`
+	Completed int    `json:"completed"`
+	Total     int    `json:"total"`
+}
+
+// StageAnomalySink recebe cada atraso de estágio detectado por ReportStage —
+// quando RealCompletedAt acaba passando de PlanCompletedAt — no mesmo
+// espírito de AnomalySink, mas no domínio de Task.Stages em vez de
+// AgentHealth, já que um atraso de estágio não é, por si só, uma falha de
+// agente.
+type StageAnomalySink func(taskID, stageName string, planned, actual time.Time)
+
+// AgentReporter permite que um agente reporte o progresso de uma Task de
+// múltiplas fases incrementalmente, por estágio, em vez de só seu TaskStatus
+// terminal.
+type AgentReporter interface {
+	// ReportStage atualiza o estágio stageName da tarefa taskID para status,
+	// com desc como StatusDescription, e devolve o progresso agregado da
+	// tarefa. stageName precisa já existir em Task.Stages — os estágios de
+	// uma Task são declarados antecipadamente por quem a monta, não
+	// inventados em tempo de execução.
+	ReportStage(taskID, stageName string, status TaskStageStatus, desc string) (StageProgress, error)
+}
+
+// taskManagerReporter implementa AgentReporter sobre um *TaskManager.
+type taskManagerReporter struct {
+	tm    *TaskManager
+	sinks []StageAnomalySink
+}
+
+// NewAgentReporter cria um AgentReporter que atualiza os Stages das Task
+// conhecidas por tm, notificando sinks sempre que um estágio conclui depois
+// de seu PlanCompletedAt.
+func NewAgentReporter(tm *TaskManager, sinks ...StageAnomalySink) AgentReporter {
+	return &taskManagerReporter{tm: tm, sinks: sinks}
+}
+
+// ReportStage implementa AgentReporter.
+func (r *taskManagerReporter) ReportStage(taskID, stageName string, status TaskStageStatus, desc string) (StageProgress, error) {
+	r.tm.Lock()
+	defer r.tm.Unlock()
+
+	task, exists := r.tm.tasks[taskID]
+	if !exists {
+		return StageProgress{}, fmt.Errorf("tarefa %s não encontrada", taskID)
+	}
+
+	idx := -1
+	for i := range task.Stages {
+		if task.Stages[i].Name == stageName {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return StageProgress{}, fmt.Errorf("tarefa %s não declara o estágio %q", taskID, stageName)
+	}
+
+	stage := &task.Stages[idx]
+	stage.Status = status
+	stage.StatusDescription = desc
+
+	if status == StageStatusComplete {
+		now := time.Now()
+		stage.RealCompletedAt = &now
+
+		if stage.PlanCompletedAt != nil && now.After(*stage.PlanCompletedAt) {
+			log.Printf("⏱️ estágio %q da tarefa %s concluiu atrasado: planejado para %s, concluído às %s",
+				stageName, taskID, stage.PlanCompletedAt.Format(time.RFC3339), now.Format(time.RFC3339))
+
+			for _, sink := range r.sinks {
+				sink(taskID, stageName, *stage.PlanCompletedAt, now)
+			}
+		}
+	}
+
+	completed, total := task.Progress()
+	return StageProgress{TaskID: taskID, Completed: completed, Total: total}, nil
+}
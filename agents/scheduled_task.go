@@ -0,0 +1,271 @@
+package agents
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// OverlapPolicy define o que o Scheduler faz quando um ScheduledTask dispara
+// enquanto instâncias de seu disparo anterior ainda não terminaram.
+type OverlapPolicy string
+
+const (
+	// OverlapSkip descarta o disparo atual, mantendo as instâncias anteriores
+	// em execução. É o padrão quando Overlap é deixado em branco.
+	OverlapSkip OverlapPolicy = "skip"
+
+	// OverlapQueue despacha o disparo atual normalmente, mesmo acima de
+	// MaxConcurrent — "queue" no sentido de deixar o TaskManager enfileirar
+	// e atribuir quando houver capacidade, em vez de o Scheduler recusar.
+	OverlapQueue OverlapPolicy = "queue"
+
+	// OverlapCancelPrevious pede o encerramento das instâncias anteriores
+	// ainda não terminadas antes de despachar a nova.
+	OverlapCancelPrevious OverlapPolicy = "cancel_previous"
+)
+
+// ScheduledTask descreve uma Task a ser materializada repetidamente segundo
+// Spec — cron clássico de 6 campos com segundos ou shorthand (@hourly,
+// @every 1h30m, ...), no mesmo dialeto aceito por github.com/robfig/cron.
+// Cada disparo clona Template numa nova Task com ID e CreatedAt próprios;
+// Template.ID é ignorado.
+type ScheduledTask struct {
+	ID            string
+	Template      Task
+	Spec          string
+	MaxConcurrent int
+	Overlap       OverlapPolicy
+
+	NextRun time.Time
+	LastRun *time.Time
+
+	cronID    cron.EntryID
+	activeIDs []string
+}
+
+// ScheduledTaskEntry é um snapshot somente-leitura de um ScheduledTask,
+// devolvido por Scheduler.Entries para inspeção e dashboards.
+type ScheduledTaskEntry struct {
+	ID      string
+	Spec    string
+	NextRun time.Time
+	LastRun *time.Time
+}
+
+// Scheduler materializa Task a partir de ScheduledTask em seus horários de
+// disparo e as despacha através de TaskManager.AddTask, honrando
+// MaxConcurrent e a OverlapPolicy de cada entrada. Embrulha um *cron.Cron com
+// segundos habilitados, no mesmo espírito de cronScheduler em scripts.go, mas
+// aqui materializando Task em vez de rodar scripts de agente.
+type Scheduler struct {
+	cron        *cron.Cron
+	taskManager *TaskManager
+
+	mu      sync.RWMutex
+	entries map[string]*ScheduledTask
+}
+
+// NewScheduler cria um Scheduler que despacha Task materializadas através de
+// tm, interpretando Spec no fuso horário loc. loc nil usa time.Local, no
+// mesmo padrão de time.ParseInLocation.
+func NewScheduler(tm *TaskManager, loc *time.Location) *Scheduler {
+	opts := []cron.Option{cron.WithSeconds()}
+	if loc != nil {
+		opts = append(opts, cron.WithLocation(loc))
+	}
+
+	return &Scheduler{
+		cron:        cron.New(opts...),
+		taskManager: tm,
+		entries:     make(map[string]*ScheduledTask),
+	}
+}
+
+// Start inicia o laço de disparo do Scheduler.
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop interrompe o Scheduler, aguardando disparos em andamento terminarem.
+func (s *Scheduler) Stop() {
+	<-s.cron.Stop().Done()
+}
+
+// Schedule registra um novo ScheduledTask a partir de spec (cron clássico de
+// 6 campos ou shorthand @every/@hourly/...), retornando erro se spec for
+// inválido ou se id já estiver em uso. template.ID é ignorado: cada disparo
+// gera um ID próprio, derivado de id.
+func (s *Scheduler) Schedule(id string, template Task, spec string, maxConcurrent int, overlap OverlapPolicy) (*ScheduledTask, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.entries[id]; exists {
+		return nil, fmt.Errorf("tarefa agendada %s já existe", id)
+	}
+
+	st := &ScheduledTask{
+		ID:            id,
+		Template:      template,
+		Spec:          spec,
+		MaxConcurrent: maxConcurrent,
+		Overlap:       overlap,
+	}
+
+	cronID, err := s.cron.AddFunc(spec, func() { s.fire(st) })
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar spec de agendamento %q: %v", spec, err)
+	}
+	st.cronID = cronID
+	st.NextRun = s.entryNext(cronID)
+
+	s.entries[id] = st
+	return st, nil
+}
+
+// Unschedule remove um ScheduledTask, impedindo disparos futuros — instâncias
+// já despachadas continuam seu ciclo de vida normalmente.
+func (s *Scheduler) Unschedule(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, exists := s.entries[id]
+	if !exists {
+		return
+	}
+	s.cron.Remove(st.cronID)
+	delete(s.entries, id)
+}
+
+// Entries retorna um snapshot de todas as tarefas agendadas atualmente
+// registradas no Scheduler.
+func (s *Scheduler) Entries() []ScheduledTaskEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]ScheduledTaskEntry, 0, len(s.entries))
+	for _, st := range s.entries {
+		entries = append(entries, ScheduledTaskEntry{
+			ID:      st.ID,
+			Spec:    st.Spec,
+			NextRun: st.NextRun,
+			LastRun: st.LastRun,
+		})
+	}
+	return entries
+}
+
+// NextRun retorna o próximo horário de disparo do ScheduledTask id. O
+// segundo retorno é false se nenhum ScheduledTask com esse id existir.
+func (s *Scheduler) NextRun(id string) (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	st, exists := s.entries[id]
+	if !exists {
+		return time.Time{}, false
+	}
+	return st.NextRun, true
+}
+
+// entryNext consulta o *cron.Cron pelo próximo horário de disparo de
+// cronID — chamado com s.mu já travado.
+func (s *Scheduler) entryNext(cronID cron.EntryID) time.Time {
+	for _, entry := range s.cron.Entries() {
+		if entry.ID == cronID {
+			return entry.Next
+		}
+	}
+	return time.Time{}
+}
+
+// isTerminal indica se status marca o fim do ciclo de vida de uma Task,
+// segundo taskTransitions — usado para podar activeIDs antes de aplicar
+// MaxConcurrent/OverlapPolicy.
+func isTerminal(status TaskStatus) bool {
+	switch status {
+	case TaskStatusComplete, TaskStatusRetained, TaskStatusFailed, TaskStatusRejected, TaskStatusShutdown:
+		return true
+	default:
+		return false
+	}
+}
+
+// fire materializa e despacha uma nova Task a partir de st.Template,
+// honrando MaxConcurrent e a OverlapPolicy configurada. As instâncias
+// anteriores ainda não terminadas são identificadas consultando
+// taskManager.GetTask a cada disparo, já que o Scheduler não é notificado de
+// conclusões em tempo real.
+func (s *Scheduler) fire(st *ScheduledTask) {
+	s.mu.Lock()
+
+	active := st.activeIDs[:0]
+	for _, id := range st.activeIDs {
+		if task, ok := s.taskManager.GetTask(id); ok && !isTerminal(task.Status) {
+			active = append(active, id)
+		}
+	}
+	st.activeIDs = active
+
+	if st.MaxConcurrent > 0 && len(st.activeIDs) >= st.MaxConcurrent {
+		switch st.Overlap {
+		case OverlapCancelPrevious:
+			s.cancelActive(st)
+		case OverlapQueue:
+			// segue adiante e despacha mesmo acima do limite
+		default: // OverlapSkip, ou Overlap não configurado
+			s.mu.Unlock()
+			log.Printf("⏭️ agendamento %s pulado: %d instâncias ainda em execução", st.ID, len(st.activeIDs))
+			return
+		}
+	}
+
+	task := st.Template
+	task.ID = fmt.Sprintf("%s-%d", st.ID, time.Now().UnixNano())
+	task.Status = TaskStatusPending
+
+	now := time.Now()
+	st.LastRun = &now
+	st.NextRun = s.entryNext(st.cronID)
+	st.activeIDs = append(st.activeIDs, task.ID)
+
+	s.mu.Unlock()
+
+	if _, err := s.taskManager.AddTask(&task); err != nil {
+		log.Printf("⚠️ falha ao despachar tarefa agendada %s: %v", st.ID, err)
+		return
+	}
+
+	log.Printf("⏰ agendamento %s disparou, nova instância %s", st.ID, task.ID)
+}
+
+// cancelActive pede, via Task.Transition, o encerramento de todas as
+// instâncias ainda ativas de st — chamado com s.mu já travado. Tarefas
+// Running recebem DesiredState=Shutdown seguido de Transition para
+// TaskStatusShutdown; tarefas em qualquer outro estado não-terminal são
+// apenas rejeitadas, já que ainda não começaram a rodar.
+func (s *Scheduler) cancelActive(st *ScheduledTask) {
+	for _, id := range st.activeIDs {
+		task, ok := s.taskManager.GetTask(id)
+		if !ok {
+			continue
+		}
+
+		if task.Status == TaskStatusRunning {
+			task.DesiredState = TaskStatusShutdown
+			if err := task.Transition(TaskStatusShutdown); err != nil {
+				log.Printf("⚠️ falha ao cancelar instância %s do agendamento %s: %v", id, st.ID, err)
+			}
+			continue
+		}
+
+		if err := task.Transition(TaskStatusRejected); err != nil {
+			log.Printf("⚠️ falha ao cancelar instância %s do agendamento %s: %v", id, st.ID, err)
+		}
+	}
+
+	st.activeIDs = nil
+}
@@ -0,0 +1,150 @@
+package agents
+
+import (
+	"container/heap"
+	"fmt"
+	"time"
+)
+
+// Pesos usados por scoreCandidate para combinar prioridade, idade na fila,
+// penalidade de retry e afinidade agente/tipo num único score comparável,
+// no espírito do scored-candidate approach do scheduler de tarefas do Skia.
+const (
+	schedulingPriorityWeight = 100.0 // pontos por unidade de TaskPriority
+	schedulingAgeWeight      = 0.05  // pontos por segundo de espera na fila, evita starvation
+	schedulingRetryPenalty   = 15.0  // pontos subtraídos por tentativa anterior malsucedida
+	schedulingAffinityWeight = 20.0  // pontos multiplicados pela taxa de sucesso recente do agente neste tipo
+)
+
+// taskHeap é um max-heap de *Task pendentes de um mesmo Type, ordenado por
+// Priority e, em empate, pela mais antiga primeiro. GetNextTask usa o
+// primeiro candidato elegível de cada taskHeap capaz de um agente como
+// conjunto de candidatos a pontuar, em vez de pontuar todas as tarefas
+// pendentes; o snapshot de status montado por completedStatuses ainda
+// percorre todas as tarefas a cada chamada.
+type taskHeap []*Task
+
+func (h taskHeap) Len() int { return len(h) }
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].Priority != h[j].Priority {
+		return h[i].Priority > h[j].Priority
+	}
+	return h[i].CreatedAt.Before(h[j].CreatedAt)
+}
+func (h taskHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *taskHeap) Push(x interface{}) {
+	*h = append(*h, x.(*Task))
+}
+
+func (h *taskHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	task := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return task
+}
+
+// affinityKey identifica o par agente/tipo de tarefa cuja taxa de sucesso
+// recente alimenta o termo de afinidade de scoreCandidate.
+type affinityKey struct {
+	agentName string
+	taskType  string
+}
+
+// affinityStats acumula sucessos e falhas recentes de um agente num tipo de
+// tarefa específico, usado para estimar sua taxa de sucesso atual.
+type affinityStats struct {
+	successes int
+	failures  int
+}
+
+func (s *affinityStats) successRate() float64 {
+	total := s.successes + s.failures
+	if total == 0 {
+		return 0.5 // sem histórico, nem favorece nem penaliza o candidato
+	}
+	return float64(s.successes) / float64(total)
+}
+
+// RegisterAgentCapabilities declara os tipos de tarefa (Task.Type) que
+// agentName sabe processar. GetNextTask só considera, para um agente, tarefas
+// cujo tipo esteja neste conjunto. Um agente sem capacidades registradas é
+// tratado como capaz de qualquer tipo, preservando o comportamento anterior
+// para chamadores que ainda não adotaram a API.
+func (tm *TaskManager) RegisterAgentCapabilities(agentName string, taskTypes []string) {
+	tm.Lock()
+	defer tm.Unlock()
+
+	set := make(map[string]bool, len(taskTypes))
+	for _, t := range taskTypes {
+		set[t] = true
+	}
+	tm.capabilities[agentName] = set
+}
+
+// enqueuePending insere task no taskHeap do seu Type, criando o heap sob
+// demanda. Chamado por AddTask sob tm.Lock.
+func (tm *TaskManager) enqueuePending(task *Task) {
+	h, ok := tm.pendingByType[task.Type]
+	if !ok {
+		h = &taskHeap{}
+		heap.Init(h)
+		tm.pendingByType[task.Type] = h
+	}
+	heap.Push(h, task)
+}
+
+// scoreCandidate combina prioridade, idade na fila, penalidade de retry e
+// afinidade agente/tipo num único score: quanto maior, mais adequado o
+// candidato é para agentName agora.
+func (tm *TaskManager) scoreCandidate(task *Task, agentName string) float64 {
+	score := float64(task.Priority) * schedulingPriorityWeight
+	score += time.Since(task.CreatedAt).Seconds() * schedulingAgeWeight
+	score -= float64(task.RetryCount) * schedulingRetryPenalty
+
+	if stats, ok := tm.affinity[affinityKey{agentName: agentName, taskType: task.Type}]; ok {
+		score += stats.successRate() * schedulingAffinityWeight
+	}
+
+	return score
+}
+
+// recordTaskOutcome atualiza a afinidade agente/tipo após uma tarefa ser
+// concluída ou falhar, consultado por scoreCandidate nas próximas atribuições
+// do mesmo tipo de tarefa a este agente. Chamado por UpdateTaskStatus sob
+// tm.Lock.
+func (tm *TaskManager) recordTaskOutcome(task *Task, success bool) {
+	if task.AssignedTo == "" {
+		return
+	}
+
+	key := affinityKey{agentName: task.AssignedTo, taskType: task.Type}
+	stats, ok := tm.affinity[key]
+	if !ok {
+		stats = &affinityStats{}
+		tm.affinity[key] = stats
+	}
+
+	if success {
+		stats.successes++
+	} else {
+		stats.failures++
+	}
+}
+
+// priorityLabel converte TaskPriority num valor estável para o label
+// "priority" do histograma hivemind_task_queue_time_seconds.
+func priorityLabel(p TaskPriority) string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("%d", p)
+	}
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import "sort"
+
+// AffinitySpreadScorer pontua candidatos somando os pesos de
+// PlacementConstraints que eles satisfazem e subtraindo uma penalidade
+// proporcional a quanto colocá-los pioraria o desvio frente a SpreadTarget —
+// o par affinity/spread do Nomad. É o Scorer padrão do orquestrador.
+type AffinitySpreadScorer struct {
+	// SpreadPenaltyWeight converte um desvio de spread (0..1) em pontos
+	// subtraídos do placement_score; o zero-value usa 100, equiparando um
+	// desvio máximo a uma constraint de afinidade de peso alto.
+	SpreadPenaltyWeight float64
+}
+
+// Score implementa Scorer.
+func (s AffinitySpreadScorer) Score(candidates, placed []Candidate, constraints PlacementConstraints, spreadAttribute string, spread SpreadTarget) []Decision {
+	penaltyWeight := s.SpreadPenaltyWeight
+	if penaltyWeight == 0 {
+		penaltyWeight = 100
+	}
+
+	counts := make(map[string]int, len(placed))
+	for _, c := range placed {
+		counts[c.Attributes[spreadAttribute]]++
+	}
+	total := len(placed)
+
+	decisions := make([]Decision, 0, len(candidates))
+	for _, c := range candidates {
+		deviation := spreadDeviation(c, spreadAttribute, spread, counts, total)
+
+		decisions = append(decisions, Decision{
+			Candidate:       c,
+			PlacementScore:  affinityScore(c, constraints) - penaltyWeight*deviation,
+			SpreadDeviation: deviation,
+		})
+	}
+
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return decisions[i].PlacementScore > decisions[j].PlacementScore
+	})
+
+	return decisions
+}
+
+// spreadDeviation estima o desvio absoluto entre a fração alvo, em spread,
+// do valor de spreadAttribute de c e a fração resultante de somar c a placed
+// — sem mutar counts. Um valor fora de spread é tratado como desvio máximo
+// (1), para que AffinitySpreadScorer prefira qualquer candidato já coberto
+// pelo SpreadTarget declarado.
+func spreadDeviation(c Candidate, spreadAttribute string, spread SpreadTarget, counts map[string]int, total int) float64 {
+	if len(spread) == 0 {
+		return 0
+	}
+
+	value := c.Attributes[spreadAttribute]
+	target, ok := spread[value]
+	if !ok {
+		return 1
+	}
+
+	resultingFraction := float64(counts[value]+1) / float64(total+1)
+	deviation := resultingFraction - target
+	if deviation < 0 {
+		deviation = -deviation
+	}
+	return deviation
+}
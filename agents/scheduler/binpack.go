@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"sort"
+	"strconv"
+)
+
+// BinPackScorer favorece candidatos já mais carregados, consolidando
+// instâncias em menos hosts em vez de espalhá-las (SpreadTarget é ignorado)
+// — o oposto de AffinitySpreadScorer, trocável por tipo de agente que
+// prefira empacotamento a espalhamento, ex.: agentes com afinidade de GPU
+// onde é mais barato saturar os poucos hosts com GPU do que espalhar.
+type BinPackScorer struct{}
+
+// Score implementa Scorer. A carga de um candidato é lida do atributo
+// "in_flight_tasks" (preenchido pelo orquestrador a partir do Heartbeat),
+// ausência ou valor não-numérico conta como carga zero.
+func (BinPackScorer) Score(candidates, placed []Candidate, constraints PlacementConstraints, spreadAttribute string, spread SpreadTarget) []Decision {
+	decisions := make([]Decision, 0, len(candidates))
+	for _, c := range candidates {
+		decisions = append(decisions, Decision{
+			Candidate:      c,
+			PlacementScore: affinityScore(c, constraints) + loadScore(c),
+		})
+	}
+
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return decisions[i].PlacementScore > decisions[j].PlacementScore
+	})
+
+	return decisions
+}
+
+func loadScore(c Candidate) float64 {
+	score, err := strconv.ParseFloat(c.Attributes["in_flight_tasks"], 64)
+	if err != nil {
+		return 0
+	}
+	return score
+}
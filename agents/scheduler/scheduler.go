@@ -0,0 +1,67 @@
+// Package scheduler rankeia hosts candidatos para receber uma nova
+// instância de agente, combinando preferências de afinidade com peso e uma
+// penalidade por desvio de spread — no espírito do affinity/spread
+// scheduling do Nomad.
+package scheduler
+
+// Candidate é um host candidato a receber uma nova instância, identificado
+// pelo mesmo AgentID usado no keyspace ident:* do orquestrador.
+type Candidate struct {
+	Host       string
+	Attributes map[string]string
+}
+
+// PlacementConstraints são preferências de afinidade com peso: cada chave é
+// um atributo ("gpu") ou "atributo=valor" ("region=eu"), e o valor é o peso
+// somado ao placement_score de um candidato que a satisfaz.
+type PlacementConstraints map[string]float64
+
+// SpreadTarget é a fração desejada de instâncias por valor de um atributo de
+// spread (ex.: {"dc1": 0.4, "dc2": 0.4, "dc3": 0.2}), somando até 1.0.
+type SpreadTarget map[string]float64
+
+// Decision é o resultado de pontuar um Candidate para receber a próxima
+// instância.
+type Decision struct {
+	Candidate       Candidate
+	PlacementScore  float64
+	SpreadDeviation float64
+}
+
+// Scorer rankeia candidatos para receber uma nova instância de um tipo de
+// agente, do melhor (Decision[0]) para o pior. Implementações diferentes
+// (afinidade+spread, bin-packing) podem ser trocadas por tipo de agente.
+type Scorer interface {
+	// Score rankeia candidates considerando constraints e como placed já
+	// distribui spreadAttribute segundo spread.
+	Score(candidates, placed []Candidate, constraints PlacementConstraints, spreadAttribute string, spread SpreadTarget) []Decision
+}
+
+// affinityScore soma os pesos de constraints satisfeitos por c: uma chave
+// sem "=" (ex.: "gpu") casa se o atributo existir com valor não-vazio e
+// diferente de "false"; uma chave "atributo=valor" exige igualdade exata.
+func affinityScore(c Candidate, constraints PlacementConstraints) float64 {
+	var score float64
+	for expr, weight := range constraints {
+		key, value, hasValue := splitExpr(expr)
+		if hasValue {
+			if c.Attributes[key] == value {
+				score += weight
+			}
+			continue
+		}
+		if attrValue, ok := c.Attributes[key]; ok && attrValue != "" && attrValue != "false" {
+			score += weight
+		}
+	}
+	return score
+}
+
+func splitExpr(expr string) (key, value string, hasValue bool) {
+	for i := 0; i < len(expr); i++ {
+		if expr[i] == '=' {
+			return expr[:i], expr[i+1:], true
+		}
+	}
+	return expr, "", false
+}
@@ -0,0 +1,334 @@
+package agents
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"HiveMindForge/agents/agentrpc"
+)
+
+// AgentScript é um script de efeito colateral associado a um CognitiveAgent,
+// modelado sobre o WorkspaceAgentScript do Coder: pode rodar uma vez (no
+// start e/ou no stop do agente) e/ou periodicamente segundo uma expressão
+// Cron.
+type AgentScript struct {
+	// LogSourceID identifica este script nas linhas registradas via
+	// log.Printf e nas mensagens de erro, análogo ao log source de um
+	// WorkspaceAgentScript. As LogLine enviadas via StreamLogs não o
+	// carregam: levam só o AgentID do agente.
+	LogSourceID string
+
+	// Script é o corpo do script, executado via "sh -c".
+	Script string
+
+	// Cron, se não vazio, é a expressão cron (formato robfig/cron, com
+	// segundos) em que o script roda periodicamente.
+	Cron string
+
+	RunOnStart bool
+	RunOnStop  bool
+
+	// StartBlocksLogin mantém Validate recusando novas tarefas enquanto este
+	// script, disparado por RunOnStart, ainda não terminou.
+	StartBlocksLogin bool
+
+	// Timeout, se maior que zero, encerra o script à força após esse tempo.
+	Timeout time.Duration
+}
+
+// HealthState é o estado de um Healthcheck de CognitiveAgent.
+type HealthState string
+
+const (
+	HealthStateInitializing HealthState = "initializing"
+	HealthStateHealthy      HealthState = "healthy"
+	HealthStateUnhealthy    HealthState = "unhealthy"
+	HealthStateDisabled     HealthState = "disabled"
+)
+
+// Healthcheck sonda periodicamente uma URL HTTP, transicionando para
+// HealthStateUnhealthy após Threshold falhas consecutivas — o mesmo modelo
+// de limiar do HealthCheck genérico em healthcheck.go, mas configurado
+// declarativamente por URL em vez de uma func Check customizada.
+type Healthcheck struct {
+	URL       string
+	Interval  time.Duration
+	Threshold int
+}
+
+// cronScheduler envolve *cron.Cron para que o resto do pacote não precise
+// importar robfig/cron diretamente, seguindo o mesmo padrão de AMQPBroker
+// envolvendo a biblioteca de AMQP.
+type cronScheduler struct {
+	c *cron.Cron
+}
+
+func newCronScheduler() *cronScheduler {
+	s := &cronScheduler{c: cron.New(cron.WithSeconds())}
+	s.c.Start()
+	return s
+}
+
+func (s *cronScheduler) AddFunc(spec string, fn func()) error {
+	_, err := s.c.AddFunc(spec, fn)
+	return err
+}
+
+func (s *cronScheduler) Stop() {
+	s.c.Stop()
+}
+
+// RegisterScripts associa scripts ao agente: dispara imediatamente, numa
+// goroutine, os que têm RunOnStart (se StartBlocksLogin, incrementando
+// blockingScripts antes de disparar, para que Validate veja blockingScripts
+// > 0 antes de devolver o controle ao chamador) e
+// agenda no cron os que têm Cron não vazio.
+func (a *CognitiveAgent) RegisterScripts(ctx context.Context, scripts []AgentScript) error {
+	a.scriptsLock.Lock()
+	a.Scripts = scripts
+	a.scriptsLock.Unlock()
+
+	for _, script := range scripts {
+		script := script
+
+		if script.RunOnStart {
+			if script.StartBlocksLogin {
+				atomic.AddInt32(&a.blockingScripts, 1)
+				go func() {
+					defer atomic.AddInt32(&a.blockingScripts, -1)
+					a.runScript(ctx, script)
+				}()
+			} else {
+				go a.runScript(ctx, script)
+			}
+		}
+
+		if script.Cron != "" {
+			a.scriptsLock.Lock()
+			if a.cronSched == nil {
+				a.cronSched = newCronScheduler()
+			}
+			cronSched := a.cronSched
+			a.scriptsLock.Unlock()
+
+			if err := cronSched.AddFunc(script.Cron, func() { a.runScript(ctx, script) }); err != nil {
+				return fmt.Errorf("erro ao agendar script %s (%s): %v", script.LogSourceID, script.Cron, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// runStopScripts roda, em sequência, todos os scripts com RunOnStop — chamado
+// por Stop antes de parar o cron.
+func (a *CognitiveAgent) runStopScripts() {
+	a.scriptsLock.RLock()
+	scripts := make([]AgentScript, len(a.Scripts))
+	copy(scripts, a.Scripts)
+	a.scriptsLock.RUnlock()
+
+	for _, script := range scripts {
+		if script.RunOnStop {
+			a.runScript(context.Background(), script)
+		}
+	}
+}
+
+// runScript executa script.Script via "sh -c", com prazo script.Timeout se
+// maior que zero, e encaminha cada linha de stdout/stderr como um LogLine via
+// rpcClient.StreamLogs quando houver um associado (SetAgentRPCClient), caindo
+// de volta para log.Printf caso contrário.
+func (a *CognitiveAgent) runScript(ctx context.Context, script AgentScript) {
+	runCtx := ctx
+	if script.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, script.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, "sh", "-c", script.Script)
+	output, err := cmd.CombinedOutput()
+
+	a.streamScriptOutput(ctx, script, output)
+
+	if err != nil {
+		log.Printf("⚠️ script %s terminou com erro: %v", script.LogSourceID, err)
+	}
+}
+
+// streamScriptOutput envia cada linha de output como um LogLine via
+// rpcClient.StreamLogs, ou a registra via log.Printf se não houver rpcClient
+// associado.
+func (a *CognitiveAgent) streamScriptOutput(ctx context.Context, script AgentScript, output []byte) {
+	if a.rpcClient == nil {
+		for _, line := range splitLines(output) {
+			log.Printf("📜 [%s] %s", script.LogSourceID, line)
+		}
+		return
+	}
+
+	send, closeAndRecv, err := a.rpcClient.StreamLogs(ctx)
+	if err != nil {
+		log.Printf("⚠️ falha ao abrir stream de logs para o script %s: %v", script.LogSourceID, err)
+		return
+	}
+
+	for _, line := range splitLines(output) {
+		logLine := &agentrpc.LogLine{
+			AgentID:  a.ID,
+			Level:    "info",
+			Message:  line,
+			UnixTime: time.Now().Unix(),
+		}
+		if err := send(logLine); err != nil {
+			log.Printf("⚠️ falha ao enviar linha de log do script %s: %v", script.LogSourceID, err)
+			return
+		}
+	}
+
+	if _, err := closeAndRecv(); err != nil {
+		log.Printf("⚠️ falha ao fechar stream de logs do script %s: %v", script.LogSourceID, err)
+	}
+}
+
+func splitLines(output []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+// RegisterHealthchecks inicia, para cada Healthcheck com Interval > 0 e URL
+// não vazia, uma sonda HTTP periódica via HealthCheckRegistry — as demais são
+// marcadas HealthStateDisabled e nunca rodam. O estado de cada sonda é
+// espelhado em PerformanceStats sob a chave "healthcheck:<url>".
+func (a *CognitiveAgent) RegisterHealthchecks(ctx context.Context, checks []Healthcheck) {
+	a.scriptsLock.Lock()
+	a.Healthchecks = checks
+	a.scriptsLock.Unlock()
+
+	a.healthStatesLock.Lock()
+	if a.healthStates == nil {
+		a.healthStates = make(map[string]HealthState)
+	}
+	a.healthStatesLock.Unlock()
+
+	for _, check := range checks {
+		check := check
+
+		if check.Interval <= 0 || check.URL == "" {
+			a.setHealthState(check.URL, HealthStateDisabled)
+			continue
+		}
+
+		a.setHealthState(check.URL, HealthStateInitializing)
+
+		probe := &HealthCheck{
+			Name:      "healthcheck:" + check.URL,
+			Interval:  check.Interval,
+			Threshold: check.Threshold,
+			Check: func(ctx context.Context) error {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, check.URL, nil)
+				if err != nil {
+					return fmt.Errorf("erro ao montar requisição de healthcheck: %v", err)
+				}
+
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					return fmt.Errorf("erro ao contatar %s: %v", check.URL, err)
+				}
+				defer resp.Body.Close()
+
+				if resp.StatusCode >= 400 {
+					return fmt.Errorf("%s retornou status %d", check.URL, resp.StatusCode)
+				}
+				return nil
+			},
+		}
+
+		a.RegisterHealthCheck(ctx, probe)
+		go a.watchHealthcheckState(ctx, probe.Name, check.URL)
+	}
+}
+
+// watchHealthcheckState espelha periodicamente o ProbeState agregado por
+// a.healthChecks em a.healthStates e em PerformanceStats, já que
+// HealthCheckRegistry não emite eventos de transição.
+func (a *CognitiveAgent) watchHealthcheckState(ctx context.Context, probeName, url string) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if a.healthChecks == nil {
+				continue
+			}
+			state, ok := a.healthChecks.Status(probeName)
+			if !ok {
+				continue
+			}
+
+			switch state {
+			case ProbeHealthy:
+				a.setHealthState(url, HealthStateHealthy)
+			case ProbeUnhealthy:
+				a.setHealthState(url, HealthStateUnhealthy)
+			default:
+				a.setHealthState(url, HealthStateInitializing)
+			}
+		case <-ctx.Done():
+			return
+		case <-a.stopChan:
+			return
+		}
+	}
+}
+
+// setHealthState atualiza o estado de uma Healthcheck e o reflete em
+// PerformanceStats sob a chave "healthcheck:<url>" (1 = healthy, 0 =
+// initializing, -1 = unhealthy, -2 = disabled).
+func (a *CognitiveAgent) setHealthState(url string, state HealthState) {
+	a.healthStatesLock.Lock()
+	a.healthStates[url] = state
+	a.healthStatesLock.Unlock()
+
+	var value float64
+	switch state {
+	case HealthStateHealthy:
+		value = 1
+	case HealthStateUnhealthy:
+		value = -1
+	case HealthStateDisabled:
+		value = -2
+	default:
+		value = 0
+	}
+
+	a.PerformanceStats["healthcheck:"+url] = value
+}
+
+// HealthcheckStates retorna uma cópia do estado atual de cada Healthcheck
+// registrada, indexado por URL.
+func (a *CognitiveAgent) HealthcheckStates() map[string]HealthState {
+	a.healthStatesLock.RLock()
+	defer a.healthStatesLock.RUnlock()
+
+	states := make(map[string]HealthState, len(a.healthStates))
+	for k, v := range a.healthStates {
+		states[k] = v
+	}
+	return states
+}
@@ -0,0 +1,176 @@
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TaskErrorCode classifica a causa raiz de um TaskError, usada pelo
+// dispatcher para decidir se e quando tentar novamente uma tarefa — em vez
+// de inferir isso por correspondência de texto na mensagem de erro — e pelo
+// AnomalyDetector para agrupar erros repetidos por assinatura estável.
+type TaskErrorCode string
+
+const (
+	// ErrCodeValidation marca uma entrada inválida para a tarefa (ex.: campo
+	// obrigatório ausente em Task.Data). Tentar de novo sem corrigir a
+	// entrada produz o mesmo erro, então nunca é Retryable.
+	ErrCodeValidation TaskErrorCode = "validation"
+
+	// ErrCodeTimeout marca uma tarefa que não terminou dentro do prazo
+	// esperado (ex.: runScript excedeu Script.Timeout). Tipicamente
+	// transitório, então Retryable.
+	ErrCodeTimeout TaskErrorCode = "timeout"
+
+	// ErrCodeModel marca uma falha do provedor de LLM (ex.: rate limit ou
+	// erro 5xx da API da Groq). Tipicamente transitório, então Retryable.
+	ErrCodeModel TaskErrorCode = "model"
+
+	// ErrCodeDependency marca uma tarefa reprovada por TaskManager.failDependents
+	// porque uma dependência obrigatória em Task.DependsOn terminou sem
+	// satisfazer sua DependStrategy — tentar de novo sem que a dependência
+	// seja refeita não resolve nada, então nunca é Retryable.
+	ErrCodeDependency TaskErrorCode = "dependency_failed"
+)
+
+// Issue descreve um problema específico encontrado ao validar a entrada de
+// uma tarefa, usado por TaskError.Issues.
+type Issue struct {
+	Field  string `json:"field"`
+	Detail string `json:"detail"`
+}
+
+// TaskError é o erro estruturado de uma Task, substituindo o antigo campo
+// LastError (string). Code e Retryable permitem que o dispatcher decida se e
+// quando reatribuir a tarefa sem recorrer a correspondência de texto; Issues
+// detalha falhas de validação campo a campo; Cause preserva o erro original
+// (unwrappable via errors.Is/As) sem obrigá-lo a caber no schema JSON.
+type TaskError struct {
+	Code       TaskErrorCode `json:"code"`
+	Message    string        `json:"message"`
+	Retryable  bool          `json:"retryable"`
+	Issues     []Issue       `json:"issues,omitempty"`
+	Cause      error         `json:"-"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
+
+// taskErrorJSON espelha TaskError para serialização: Cause (um error) vira a
+// string estável Cause.Error() em vez de tentar marshalar a interface, para
+// que o schema JSON continue previsível para consumidores externos como o
+// agrupamento de assinatura de erro do AnomalyDetector.
+type taskErrorJSON struct {
+	Code       TaskErrorCode `json:"code"`
+	Message    string        `json:"message"`
+	Retryable  bool          `json:"retryable"`
+	Issues     []Issue       `json:"issues,omitempty"`
+	Cause      string        `json:"cause,omitempty"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
+
+// MarshalJSON implementa json.Marshaler, achatando Cause em string.
+func (e *TaskError) MarshalJSON() ([]byte, error) {
+	aux := taskErrorJSON{
+		Code:       e.Code,
+		Message:    e.Message,
+		Retryable:  e.Retryable,
+		Issues:     e.Issues,
+		OccurredAt: e.OccurredAt,
+	}
+	if e.Cause != nil {
+		aux.Cause = e.Cause.Error()
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON implementa json.Unmarshaler. Cause chega apenas como texto —
+// não há como reconstituir o error original — então é preservado via
+// fmt.Errorf para que Error() continue legível; Unwrap não tem o que
+// devolver nesse caso.
+func (e *TaskError) UnmarshalJSON(data []byte) error {
+	var aux taskErrorJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	e.Code = aux.Code
+	e.Message = aux.Message
+	e.Retryable = aux.Retryable
+	e.Issues = aux.Issues
+	e.OccurredAt = aux.OccurredAt
+	if aux.Cause != "" {
+		e.Cause = fmt.Errorf("%s", aux.Cause)
+	}
+	return nil
+}
+
+// Error implementa a interface error.
+func (e *TaskError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap permite que errors.Is/errors.As enxerguem Cause através de um
+// TaskError.
+func (e *TaskError) Unwrap() error {
+	return e.Cause
+}
+
+// RetryableAfter informa se, e depois de quanto tempo, o dispatcher deve
+// tentar atribuir novamente uma tarefa cujo último TaskError foi e. Um Code
+// não-Retryable nunca deve ser tentado de novo. Um Code Retryable libera a
+// reatribuição após um backoff exponencial a partir de 1s, dobrando a cada
+// retryCount anterior até um teto de 30s — no mesmo espírito do backoff de
+// reconexão do AMQPBroker.
+func (e *TaskError) RetryableAfter(retryCount int) (time.Duration, bool) {
+	if e == nil || !e.Retryable {
+		return 0, false
+	}
+
+	backoff := time.Second
+	for i := 0; i < retryCount; i++ {
+		backoff *= 2
+		if backoff >= 30*time.Second {
+			return 30 * time.Second, true
+		}
+	}
+	return backoff, true
+}
+
+// NewValidationError cria um TaskError não-Retryable para entrada inválida,
+// detalhado campo a campo em issues.
+func NewValidationError(message string, issues ...Issue) *TaskError {
+	return &TaskError{
+		Code:       ErrCodeValidation,
+		Message:    message,
+		Retryable:  false,
+		Issues:     issues,
+		OccurredAt: time.Now(),
+	}
+}
+
+// NewTimeoutError cria um TaskError Retryable para uma tarefa que excedeu seu
+// prazo, preservando cause (ex.: context.DeadlineExceeded) como Cause.
+func NewTimeoutError(message string, cause error) *TaskError {
+	return &TaskError{
+		Code:       ErrCodeTimeout,
+		Message:    message,
+		Retryable:  true,
+		Cause:      cause,
+		OccurredAt: time.Now(),
+	}
+}
+
+// NewModelError cria um TaskError Retryable para uma falha do provedor de
+// LLM, preservando cause (ex.: o erro HTTP devolvido pela API da Groq).
+func NewModelError(message string, cause error) *TaskError {
+	return &TaskError{
+		Code:       ErrCodeModel,
+		Message:    message,
+		Retryable:  true,
+		Cause:      cause,
+		OccurredAt: time.Now(),
+	}
+}
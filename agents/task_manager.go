@@ -1,13 +1,18 @@
 package agents
 
 import (
+	"container/heap"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"sync"
 	"time"
 
-	"github.com/streadway/amqp"
+	"HiveMindForge/agents/hashring"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 // TaskManager gerencia a distribuição e monitoramento de tarefas
@@ -15,157 +20,258 @@ type TaskManager struct {
 	sync.RWMutex
 	tasks        map[string]*Task
 	healthStatus map[string]*AgentHealth
-	rabbitmqConn *amqp.Connection
-	rabbitmqCh   *amqp.Channel
+	broker       Broker
+
+	// anomalyDetector, se associado via SetAnomalyDetector, é consultado por
+	// GetNextTask para recusar atribuições a agentes em quarentena.
+	anomalyDetector AnomalyDetector
+
+	// pendingByType mantém, para cada Task.Type, um taskHeap das tarefas
+	// pendentes desse tipo ordenado por Priority. GetNextTask pontua o
+	// primeiro candidato elegível de cada heap cujo tipo o agente declarou
+	// saber processar em vez de pontuar todas as tarefas de tasks.
+	pendingByType map[string]*taskHeap
+
+	// capabilities registra, por agente, o conjunto de Task.Type que ele
+	// sabe processar, declarado via RegisterAgentCapabilities.
+	capabilities map[string]map[string]bool
+
+	// affinity acumula sucessos/falhas recentes por par agente/tipo de
+	// tarefa, consultado por scoreCandidate como termo de afinidade.
+	affinity map[affinityKey]*affinityStats
+
+	// queueTime observa, por label de prioridade, quanto tempo uma tarefa
+	// esperou entre AddTask e ser atribuída por GetNextTask.
+	queueTime *prometheus.HistogramVec
+
+	// ring e localID permitem que AddTask descarte tarefas cuja réplica dona,
+	// segundo o hashring, não é esta instância — evitando trabalho duplicado
+	// quando múltiplos orquestradores consomem a mesma fila.
+	ring    *hashring.HashRing
+	localID string
+
+	// retentionQueue agenda a eviction do Result das tarefas concluídas com
+	// Retention > 0, ordenadas por expiração num min-heap para que o
+	// sweeper acorde exatamente na próxima expiração em vez de varrer
+	// tasks inteiro a cada tick.
+	retentionLock  sync.Mutex
+	retentionQueue retentionHeap
+	retentionWake  chan struct{}
+
+	// started fecha assim que NewTaskManager termina de montar o
+	// TaskManager; ready fecha assim que monitorHealthEvents assinou
+	// EXCHANGE_HEALTH com sucesso e já está consumindo eventos — juntos
+	// permitem que um chamador como main.go espere o serviço realmente no ar
+	// em vez de confiar no retorno fire-and-forget do construtor.
+	started chan struct{}
+	ready   chan struct{}
+
+	// stopped fecha quando Shutdown é chamado, sinalizando monitorHealthEvents
+	// e runRetentionSweeper a encerrar. wg rastreia essas goroutines para que
+	// Shutdown só retorne depois que ambas tiverem saído. shutdownOnce torna
+	// Shutdown idempotente, no espírito do startOnce/stopOnce do agente do
+	// swarmkit.
+	stopped      chan struct{}
+	wg           sync.WaitGroup
+	shutdownOnce sync.Once
 }
 
-// NewTaskManager cria uma nova instância do gerenciador de tarefas
-func NewTaskManager() (*TaskManager, error) {
-	conn, err := amqp.Dial(fmt.Sprintf("amqp://guest:guest@%s:%d/", RABBITMQ_HOST, RABBITMQ_PORT))
-	if err != nil {
-		return nil, fmt.Errorf("falha ao conectar ao RabbitMQ: %v", err)
-	}
+// retentionEntry agenda a eviction do resultado de uma tarefa quando
+// expiresAt for alcançado.
+type retentionEntry struct {
+	taskID    string
+	expiresAt time.Time
+}
 
-	ch, err := conn.Channel()
-	if err != nil {
-		conn.Close()
-		return nil, fmt.Errorf("falha ao criar canal: %v", err)
-	}
-
-	// Declarar exchanges
-	err = ch.ExchangeDeclare(
-		EXCHANGE_HEALTH, // nome
-		"topic",         // tipo
-		QUEUE_DURABLE,   // durable
-		false,           // auto-deleted
-		false,           // internal
-		false,           // no-wait
-		nil,             // arguments
-	)
-	if err != nil {
-		ch.Close()
-		conn.Close()
+// retentionHeap é um min-heap de retentionEntry ordenado por expiresAt,
+// modelado sobre o sweeper de expiração de resultados do asynq.
+type retentionHeap []*retentionEntry
+
+func (h retentionHeap) Len() int            { return len(h) }
+func (h retentionHeap) Less(i, j int) bool  { return h[i].expiresAt.Before(h[j].expiresAt) }
+func (h retentionHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *retentionHeap) Push(x interface{}) { *h = append(*h, x.(*retentionEntry)) }
+func (h *retentionHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	*h = old[:n-1]
+	return entry
+}
+
+// SetHashRing associa um hashring compartilhado ao TaskManager. localID
+// identifica esta instância do orquestrador no anel; se vazio, AddTask não
+// faz deduplicação por posse.
+func (tm *TaskManager) SetHashRing(ring *hashring.HashRing, localID string) {
+	tm.Lock()
+	defer tm.Unlock()
+
+	tm.ring = ring
+	tm.localID = localID
+}
+
+// NewTaskManager cria uma nova instância do gerenciador de tarefas sobre o
+// Broker informado, recebido por injeção de dependência para que testes
+// possam passar um NewInMemoryBroker() em vez de discar um RabbitMQ real. reg
+// registra a série hivemind_task_queue_time_seconds; use
+// prometheus.DefaultRegisterer em produção e um prometheus.NewRegistry()
+// isolado em testes.
+func NewTaskManager(broker Broker, reg prometheus.Registerer) (*TaskManager, error) {
+	if err := broker.DeclareExchange(EXCHANGE_HEALTH, "topic"); err != nil {
 		return nil, fmt.Errorf("falha ao declarar exchange de saúde: %v", err)
 	}
 
-	err = ch.ExchangeDeclare(
-		EXCHANGE_TASK, // nome
-		"topic",       // tipo
-		QUEUE_DURABLE, // durable
-		false,         // auto-deleted
-		false,         // internal
-		false,         // no-wait
-		nil,           // arguments
-	)
-	if err != nil {
-		ch.Close()
-		conn.Close()
+	if err := broker.DeclareExchange(EXCHANGE_TASK, "topic"); err != nil {
 		return nil, fmt.Errorf("falha ao declarar exchange de tarefas: %v", err)
 	}
 
 	tm := &TaskManager{
-		tasks:        make(map[string]*Task),
-		healthStatus: make(map[string]*AgentHealth),
-		rabbitmqConn: conn,
-		rabbitmqCh:   ch,
+		tasks:         make(map[string]*Task),
+		healthStatus:  make(map[string]*AgentHealth),
+		broker:        broker,
+		pendingByType: make(map[string]*taskHeap),
+		capabilities:  make(map[string]map[string]bool),
+		affinity:      make(map[affinityKey]*affinityStats),
+		queueTime: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "hivemind_task_queue_time_seconds",
+			Help:    "Tempo entre AddTask e a atribuição da tarefa por GetNextTask, por prioridade.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"priority"}),
+		retentionWake: make(chan struct{}, 1),
+		started:       make(chan struct{}),
+		ready:         make(chan struct{}),
+		stopped:       make(chan struct{}),
 	}
 
+	tm.wg.Add(2)
+
 	// Iniciar monitoramento de saúde
 	go tm.monitorHealthEvents()
 
+	// Iniciar sweeper de eviction de resultados retidos
+	go tm.runRetentionSweeper()
+
+	close(tm.started)
+
 	return tm, nil
 }
 
-// AddTask adiciona uma nova tarefa à fila
-func (tm *TaskManager) AddTask(task *Task) {
+// Started retorna um canal fechado assim que este TaskManager terminou de
+// ser montado (exchanges declarados, goroutines internas disparadas).
+func (tm *TaskManager) Started() <-chan struct{} {
+	return tm.started
+}
+
+// Ready retorna um canal fechado assim que monitorHealthEvents assinou
+// EXCHANGE_HEALTH com sucesso e já está consumindo eventos de saúde — só
+// depois disso GetNextTask tem alguma chance de enxergar agentes saudáveis.
+func (tm *TaskManager) Ready() <-chan struct{} {
+	return tm.ready
+}
+
+// ErrTaskIDConflict é retornado por AddTask quando task.ID já pertence a uma
+// tarefa existente, permitindo que o chamador reenvie a mesma Task com
+// segurança após uma reconexão do RabbitMQ sem duplicar trabalho.
+var ErrTaskIDConflict = fmt.Errorf("já existe uma tarefa com este ID")
+
+// AddTask adiciona uma nova tarefa à fila e retorna a própria tarefa para
+// que o chamador possa inspecioná-la após os ajustes feitos aqui (CreatedAt,
+// Status). task.ID deve ser não-vazio e único: retorna ErrTaskIDConflict se
+// já existir uma tarefa com o mesmo ID. Se um hashring estiver configurado e
+// esta instância não for a dona da tarefa segundo o anel, a publicação é
+// ignorada localmente para evitar que múltiplos orquestradores processem a
+// mesma tarefa.
+func (tm *TaskManager) AddTask(task *Task) (*Task, error) {
 	tm.Lock()
 	defer tm.Unlock()
 
+	select {
+	case <-tm.stopped:
+		return nil, fmt.Errorf("task manager em desligamento, não aceita novas tarefas")
+	default:
+	}
+
+	if task.ID == "" {
+		return nil, fmt.Errorf("task.ID não pode ser vazio")
+	}
+
+	if _, exists := tm.tasks[task.ID]; exists {
+		return nil, ErrTaskIDConflict
+	}
+
+	for _, dep := range task.DependsOn {
+		if dep.TaskID == task.ID {
+			return nil, fmt.Errorf("tarefa %s não pode depender de si mesma", task.ID)
+		}
+	}
+
+	if tm.ring != nil && tm.localID != "" {
+		if owner, ok := tm.ring.OwnerOf(task.ID); ok && owner != tm.localID {
+			log.Printf("↪️ Tarefa %s pertence ao agente %s, ignorando localmente", task.ID, owner)
+			return task, nil
+		}
+	}
+
 	task.CreatedAt = time.Now()
 	task.Status = TaskStatusPending
 	tm.tasks[task.ID] = task
+	tm.enqueuePending(task)
 
-	// Publicar evento de nova tarefa
+	// Publicar evento de nova tarefa; task.Retention viaja no mesmo payload
+	// JSON, já que é apenas mais um campo de Task.
 	body, err := json.Marshal(task)
 	if err != nil {
-		log.Printf("Erro ao converter tarefa para JSON: %v", err)
-		return
+		return nil, fmt.Errorf("erro ao converter tarefa para JSON: %v", err)
 	}
 
-	err = tm.rabbitmqCh.Publish(
-		EXCHANGE_TASK,                     // exchange
-		fmt.Sprintf("task.%s", task.Type), // routing key
-		false,                             // mandatory
-		false,                             // immediate
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         body,
-			DeliveryMode: MESSAGE_PERSISTENT, // persistente
-		})
-
-	if err != nil {
-		log.Printf("Erro ao publicar tarefa: %v", err)
-		return
+	if err := tm.broker.Publish(EXCHANGE_TASK, fmt.Sprintf("task.%s", task.Type), body); err != nil {
+		return nil, fmt.Errorf("erro ao publicar tarefa: %v", err)
 	}
 
 	log.Printf("📋 Nova tarefa adicionada: %s (Tipo: %s, Prioridade: %d)",
 		task.ID, task.Type, task.Priority)
+
+	return task, nil
 }
 
-// monitorHealthEvents monitora os eventos de saúde dos agentes
+// monitorHealthEvents monitora os eventos de saúde dos agentes. O Subscribe
+// via Broker sobrevive a quedas de conexão: no AMQPBroker, a reconexão
+// re-assina o binding "health.*" automaticamente, então este loop não
+// precisa se preocupar em reiniciar sozinho. Encerra assim que tm.stopped é
+// fechado por Shutdown.
 func (tm *TaskManager) monitorHealthEvents() {
-	q, err := tm.rabbitmqCh.QueueDeclare(
-		QUEUE_HEALTH_MONITOR, // nome
-		QUEUE_DURABLE,        // durable
-		QUEUE_AUTO_DELETE,    // delete when unused
-		QUEUE_EXCLUSIVE,      // exclusive
-		QUEUE_NO_WAIT,        // no-wait
-		nil,                  // arguments
-	)
-	if err != nil {
-		log.Printf("Erro ao declarar fila de saúde: %v", err)
-		return
-	}
-
-	err = tm.rabbitmqCh.QueueBind(
-		q.Name,          // queue name
-		"health.*",      // routing key
-		EXCHANGE_HEALTH, // exchange
-		false,
-		nil,
-	)
-	if err != nil {
-		log.Printf("Erro ao fazer binding da fila de saúde: %v", err)
-		return
-	}
+	defer tm.wg.Done()
 
-	msgs, err := tm.rabbitmqCh.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
+	msgs, err := tm.broker.Subscribe(EXCHANGE_HEALTH, "health.*")
 	if err != nil {
-		log.Printf("Erro ao consumir eventos de saúde: %v", err)
+		log.Printf("Erro ao assinar eventos de saúde: %v", err)
 		return
 	}
 
-	for msg := range msgs {
-		var health AgentHealth
-		if err := json.Unmarshal(msg.Body, &health); err != nil {
-			log.Printf("Erro ao decodificar evento de saúde: %v", err)
-			continue
+	close(tm.ready)
+
+	for {
+		select {
+		case body, ok := <-msgs:
+			if !ok {
+				return
+			}
+
+			var health AgentHealth
+			if err := json.Unmarshal(body, &health); err != nil {
+				log.Printf("Erro ao decodificar evento de saúde: %v", err)
+				continue
+			}
+
+			tm.Lock()
+			tm.healthStatus[health.AgentName] = &health
+			tm.Unlock()
+
+			log.Printf("❤️ Heartbeat recebido do agente %s (Processando: %v)",
+				health.AgentName, health.IsProcessing)
+		case <-tm.stopped:
+			return
 		}
-
-		tm.Lock()
-		tm.healthStatus[health.AgentName] = &health
-		tm.Unlock()
-
-		log.Printf("❤️ Heartbeat recebido do agente %s (Processando: %v)",
-			health.AgentName, health.IsProcessing)
 	}
 }
 
@@ -176,18 +282,7 @@ func (tm *TaskManager) EmitHealthSignal(health *AgentHealth) error {
 		return fmt.Errorf("erro ao codificar evento de saúde: %v", err)
 	}
 
-	err = tm.rabbitmqCh.Publish(
-		EXCHANGE_HEALTH, // exchange
-		fmt.Sprintf("health.%s", health.AgentName), // routing key
-		false, // mandatory
-		false, // immediate
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         body,
-			DeliveryMode: MESSAGE_PERSISTENT, // persistente
-		})
-
-	if err != nil {
+	if err := tm.broker.Publish(EXCHANGE_HEALTH, fmt.Sprintf("health.%s", health.AgentName), body); err != nil {
 		return fmt.Errorf("erro ao publicar evento de saúde: %v", err)
 	}
 
@@ -211,43 +306,343 @@ func (tm *TaskManager) GetNextTask(agentName string) *Task {
 		return nil
 	}
 
+	// Recusar atribuir trabalho a um agente cujas HealthCheck o marcaram
+	// como Unhealthy (ex.: API da Groq ou canal AMQP fora do ar).
+	if health.ProbeState == ProbeUnhealthy {
+		log.Printf("⚠️ Agente %s está unhealthy (sondas), recusando atribuição", agentName)
+		return nil
+	}
+
+	// Recusar atribuir trabalho a um agente em quarentena por anomalias
+	// consecutivas (heartbeat, success rate, processing time, erro repetido).
+	if tm.anomalyDetector != nil && tm.anomalyDetector.IsQuarantined(agentName) {
+		log.Printf("⚠️ Agente %s está em quarentena por anomalias, recusando atribuição", agentName)
+		return nil
+	}
+
+	// Restringir aos tipos que o agente declarou saber processar via
+	// RegisterAgentCapabilities; um agente sem capacidades registradas é
+	// tratado como capaz de qualquer tipo.
+	caps := tm.capabilities[agentName]
+	restricted := len(caps) > 0
+
+	completed := tm.completedStatuses()
+
 	var bestTask *Task
-	highestPriority := PriorityLow - 1
+	var bestHeap *taskHeap
+	bestScore := 0.0
+
+	for taskType, h := range tm.pendingByType {
+		if h.Len() == 0 {
+			continue
+		}
+		if restricted && !caps[taskType] {
+			continue
+		}
+
+		candidate := tm.popEligibleCandidate(h, completed, agentName)
+		if candidate == nil {
+			continue
+		}
+
+		score := tm.scoreCandidate(candidate, agentName)
+
+		if bestTask == nil || score > bestScore {
+			if bestTask != nil {
+				// Não é mais o melhor candidato visto até agora — devolve ao
+				// seu heap em vez de descartá-lo.
+				heap.Push(bestHeap, bestTask)
+			}
+			bestTask = candidate
+			bestHeap = h
+			bestScore = score
+		} else {
+			heap.Push(h, candidate)
+		}
+	}
+
+	if bestTask == nil {
+		return nil
+	}
+
+	if err := bestTask.Transition(TaskStatusAssigned); err != nil {
+		log.Printf("⚠️ transição inválida ao atribuir tarefa %s: %v", bestTask.ID, err)
+	}
+	bestTask.AssignedTo = agentName
+	tm.queueTime.WithLabelValues(priorityLabel(bestTask.Priority)).Observe(time.Since(bestTask.CreatedAt).Seconds())
+
+	log.Printf("✅ Tarefa %s atribuída ao agente %s (score %.2f)", bestTask.ID, agentName, bestScore)
+
+	return bestTask
+}
+
+// UpdateTaskStatus atualiza o status de uma tarefa, validando a transição via
+// Task.Transition — status que não é alcançável a partir do Status atual da
+// tarefa é recusado sem efeito colateral algum. Ao transicionar para
+// TaskStatusComplete, persiste o Result acumulado e, se a tarefa tiver
+// Retention > 0, passa para TaskStatusRetained e agenda a eviction do
+// resultado para quando Retention elapsed.
+func (tm *TaskManager) UpdateTaskStatus(taskID string, status TaskStatus) error {
+	tm.Lock()
+
+	task, exists := tm.tasks[taskID]
+	if !exists {
+		tm.Unlock()
+		return fmt.Errorf("tarefa %s não encontrada", taskID)
+	}
+
+	if err := task.Transition(status); err != nil {
+		tm.Unlock()
+		return err
+	}
+
+	switch status {
+	case TaskStatusComplete:
+		now := time.Now()
+		task.CompletedAt = &now
+		tm.recordTaskOutcome(task, true)
+
+		if task.Retention > 0 {
+			_ = task.Transition(TaskStatusRetained)
+			tm.scheduleEviction(taskID, now.Add(task.Retention))
+		}
+	case TaskStatusFailed:
+		task.RetryCount++
+		tm.recordTaskOutcome(task, false)
+		tm.failDependents(taskID, task)
+	case TaskStatusRejected:
+		tm.failDependents(taskID, task)
+	}
+
+	finalStatus := task.Status
+	tm.Unlock()
+
+	log.Printf("🔄 Status da tarefa %s atualizado para: %s", taskID, finalStatus)
+	return nil
+}
 
-	// Encontrar a tarefa pendente com maior prioridade
+// completedStatuses monta o snapshot de TaskStatus por ID exigido por
+// Task.Ready, a partir de tm.tasks — chamado com tm.Lock já travado.
+func (tm *TaskManager) completedStatuses() map[string]TaskStatus {
+	statuses := make(map[string]TaskStatus, len(tm.tasks))
+	for id, t := range tm.tasks {
+		statuses[id] = t.Status
+	}
+	return statuses
+}
+
+// popEligibleCandidate remove e devolve o primeiro candidato elegível do
+// taskHeap h — pulando, e devolvendo ao heap, qualquer candidato bloqueado
+// por Ready, sameAgentSatisfied ou retryEligible — em vez de só espiar a
+// raiz do heap e desistir do tipo inteiro quando ela está bloqueada. Sem
+// isso, uma única tarefa travada numa dependência pendente (chunk3-3) ou em
+// backoff de retry (chunk3-6) faminaria indefinidamente as demais tarefas
+// pendentes do mesmo tipo. Devolve nil, restaurando h ao estado original, se
+// nenhum candidato estiver elegível agora. Chamado com tm.Lock já travado.
+func (tm *TaskManager) popEligibleCandidate(h *taskHeap, completed map[string]TaskStatus, agentName string) *Task {
+	var skipped []*Task
+
+	for h.Len() > 0 {
+		candidate := heap.Pop(h).(*Task)
+
+		if candidate.Ready(completed) && tm.sameAgentSatisfied(candidate, agentName) && tm.retryEligible(candidate) {
+			for _, s := range skipped {
+				heap.Push(h, s)
+			}
+			return candidate
+		}
+
+		skipped = append(skipped, candidate)
+	}
+
+	for _, s := range skipped {
+		heap.Push(h, s)
+	}
+	return nil
+}
+
+// retryEligible informa se candidate pode ser atribuído agora, consultando
+// seu TaskError mais recente em vez de inferir isso por correspondência de
+// texto na mensagem de erro: um Code não-Retryable torna a tarefa
+// definitivamente inviável, e um Code Retryable ainda dentro da janela de
+// backoff de TaskError.RetryableAfter a mantém na fila sem atribuí-la.
+// Chamado com tm.Lock já travado.
+func (tm *TaskManager) retryEligible(candidate *Task) bool {
+	if candidate.Error == nil {
+		return true
+	}
+
+	wait, retryable := candidate.Error.RetryableAfter(candidate.RetryCount)
+	if !retryable {
+		return false
+	}
+	return time.Since(candidate.Error.OccurredAt) >= wait
+}
+
+// sameAgentSatisfied confere as dependências DependSameAgent de candidate,
+// que Task.Ready não consegue validar sozinho por não conhecer agentName:
+// exige que o agente que concluiu cada pai seja o mesmo prestes a receber
+// candidate. Chamado com tm.Lock já travado.
+func (tm *TaskManager) sameAgentSatisfied(candidate *Task, agentName string) bool {
+	for _, dep := range candidate.DependsOn {
+		if dep.Strategy != DependSameAgent {
+			continue
+		}
+		parent, ok := tm.tasks[dep.TaskID]
+		if !ok || parent.AssignedTo != agentName {
+			return false
+		}
+	}
+	return true
+}
+
+// failDependents marca como TaskStatusFailed, com um LastError descritivo,
+// toda task ainda pendente que dependia de parentID com uma DependStrategy
+// diferente de DependAny, e propaga recursivamente a quem dependia dessas —
+// uma falha encadeada nunca mais poderá satisfazer Ready(). A recursão
+// termina porque NewWorkflow rejeita ciclos na submissão. Chamado com
+// tm.Lock já travado.
+func (tm *TaskManager) failDependents(parentID string, parent *Task) {
 	for _, task := range tm.tasks {
 		if task.Status != TaskStatusPending {
 			continue
 		}
 
-		if task.Type == health.AgentName && task.Priority > highestPriority {
-			bestTask = task
-			highestPriority = task.Priority
+		for _, dep := range task.DependsOn {
+			if dep.TaskID != parentID || dep.Strategy == DependAny {
+				continue
+			}
+
+			task.Error = &TaskError{
+				Code:       ErrCodeDependency,
+				Message:    fmt.Sprintf("dependência %s terminou em %s", parentID, parent.Status),
+				Retryable:  false,
+				OccurredAt: time.Now(),
+			}
+			if err := task.Transition(TaskStatusFailed); err != nil {
+				log.Printf("⚠️ falha ao propagar falha de %s para %s: %v", parentID, task.ID, err)
+				break
+			}
+
+			tm.recordTaskOutcome(task, false)
+			tm.failDependents(task.ID, task)
+			break
 		}
 	}
+}
+
+// GetTask retorna a Task identificada por taskID, se existir — usado pelo
+// Scheduler para inspecionar o estado da última instância materializada de
+// um ScheduledTask antes de decidir como aplicar sua OverlapPolicy.
+func (tm *TaskManager) GetTask(taskID string) (*Task, bool) {
+	tm.RLock()
+	defer tm.RUnlock()
+	task, exists := tm.tasks[taskID]
+	return task, exists
+}
+
+// GetTaskResult retorna o Result acumulado de uma tarefa concluída. O
+// segundo retorno é false se a tarefa não existir ou ainda não tiver sido
+// concluída (ou se seu resultado já foi evictado por Retention).
+func (tm *TaskManager) GetTaskResult(taskID string) ([]byte, bool) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	task, exists := tm.tasks[taskID]
+	if !exists || (task.Status != TaskStatusComplete && task.Status != TaskStatusRetained) {
+		return nil, false
+	}
+	return task.Result, true
+}
 
-	if bestTask != nil {
-		bestTask.Status = TaskStatusAssigned
-		bestTask.AssignedTo = agentName
-		log.Printf("✅ Tarefa %s atribuída ao agente %s", bestTask.ID, agentName)
+// scheduleEviction agenda a remoção da tarefa taskID quando expiresAt for
+// alcançado, acordando o sweeper caso essa expiração seja mais próxima do que
+// a que ele já estava aguardando.
+func (tm *TaskManager) scheduleEviction(taskID string, expiresAt time.Time) {
+	tm.retentionLock.Lock()
+	heap.Push(&tm.retentionQueue, &retentionEntry{taskID: taskID, expiresAt: expiresAt})
+	tm.retentionLock.Unlock()
+
+	select {
+	case tm.retentionWake <- struct{}{}:
+	default:
 	}
+}
 
-	return bestTask
+// runRetentionSweeper dorme até a próxima expiração agendada em
+// retentionQueue e então evicta as tarefas correspondentes, acordando antes
+// do tempo sempre que scheduleEviction agenda uma expiração mais próxima.
+func (tm *TaskManager) runRetentionSweeper() {
+	defer tm.wg.Done()
+
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		tm.retentionLock.Lock()
+		wait := time.Hour
+		if tm.retentionQueue.Len() > 0 {
+			wait = time.Until(tm.retentionQueue[0].expiresAt)
+			if wait < 0 {
+				wait = 0
+			}
+		}
+		tm.retentionLock.Unlock()
+
+		timer.Reset(wait)
+
+		select {
+		case <-timer.C:
+			tm.evictExpired()
+		case <-tm.retentionWake:
+			if !timer.Stop() {
+				<-timer.C
+			}
+		case <-tm.stopped:
+			return
+		}
+	}
 }
 
-// UpdateTaskStatus atualiza o status de uma tarefa
-func (tm *TaskManager) UpdateTaskStatus(taskID string, status TaskStatus) {
+// evictExpired remove do mapa de tarefas todas as entradas de
+// retentionQueue cuja expiração já passou.
+func (tm *TaskManager) evictExpired() {
+	now := time.Now()
+
+	tm.retentionLock.Lock()
+	var expiredIDs []string
+	for tm.retentionQueue.Len() > 0 && !tm.retentionQueue[0].expiresAt.After(now) {
+		entry := heap.Pop(&tm.retentionQueue).(*retentionEntry)
+		expiredIDs = append(expiredIDs, entry.taskID)
+	}
+	tm.retentionLock.Unlock()
+
+	if len(expiredIDs) == 0 {
+		return
+	}
+
 	tm.Lock()
-	defer tm.Unlock()
+	for _, id := range expiredIDs {
+		delete(tm.tasks, id)
+	}
+	tm.Unlock()
+
+	log.Printf("🗑️ %d resultado(s) de tarefa expirado(s) por retention", len(expiredIDs))
+}
 
-	if task, exists := tm.tasks[taskID]; exists {
-		task.Status = status
-		if status == TaskStatusComplete {
-			now := time.Now()
-			task.CompletedAt = &now
+// QueueDepth retorna o número de tarefas pendentes na fila, usado pelo
+// ObserverInfrastructureAgent para exportar hivemind_observer_task_queue_depth.
+func (tm *TaskManager) QueueDepth() int {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	depth := 0
+	for _, task := range tm.tasks {
+		if task.Status == TaskStatusPending {
+			depth++
 		}
-		log.Printf("🔄 Status da tarefa %s atualizado para: %s", taskID, status)
 	}
+	return depth
 }
 
 // GetAgentHealth retorna o estado de saúde de um agente
@@ -256,3 +651,108 @@ func (tm *TaskManager) GetAgentHealth(agentName string) *AgentHealth {
 	defer tm.RUnlock()
 	return tm.healthStatus[agentName]
 }
+
+// SetAnomalyDetector associa o AnomalyDetector consultado por GetNextTask
+// para recusar atribuições a agentes em quarentena (IsQuarantined). Passar
+// nil desativa a checagem.
+func (tm *TaskManager) SetAnomalyDetector(detector AnomalyDetector) {
+	tm.Lock()
+	defer tm.Unlock()
+	tm.anomalyDetector = detector
+}
+
+// snapshotHealth retorna uma cópia rasa de healthStatus, para que
+// RunAnomalyDetector possa iterar sobre o AgentHealth de cada agente sem
+// segurar tm.Lock durante toda a avaliação (potencialmente lenta, por causa
+// dos callbacks de AnomalySink).
+func (tm *TaskManager) snapshotHealth() map[string]*AgentHealth {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	snapshot := make(map[string]*AgentHealth, len(tm.healthStatus))
+	for name, health := range tm.healthStatus {
+		snapshot[name] = health
+	}
+	return snapshot
+}
+
+// GetAgentProbeStatus retorna o estado da sonda probeName do agente
+// agentName, conforme o último AgentHealth recebido em EXCHANGE_HEALTH. O
+// segundo retorno é false se o agente ou a sonda forem desconhecidos.
+func (tm *TaskManager) GetAgentProbeStatus(agentName, probeName string) (ProbeState, bool) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	health, exists := tm.healthStatus[agentName]
+	if !exists {
+		return "", false
+	}
+
+	state, ok := health.Probes[probeName]
+	return state, ok
+}
+
+// IsChannelAlive informa se o Broker deste TaskManager continua apto a
+// publicar e consumir mensagens, usado pela sonda embutida
+// NewRabbitMQLivenessProbe.
+func (tm *TaskManager) IsChannelAlive() bool {
+	return tm.broker.Alive()
+}
+
+// Shutdown para o TaskManager de forma graciosa: (1) fecha stopped, o que de
+// uma vez faz AddTask recusar novas tarefas e encerra monitorHealthEvents e
+// runRetentionSweeper, (2) espera as tarefas Assigned/Running chegarem a um
+// estado terminal até o prazo de ctx, (3) aguarda aquelas duas goroutines
+// saírem, e (4) fecha o Broker. Eventos de saúde publicados durante a espera
+// de (2) não são mais consumidos. Chamadas
+// subsequentes são no-ops — idempotente via sync.Once, no espírito do
+// startOnce/stopOnce do agente do swarmkit.
+func (tm *TaskManager) Shutdown(ctx context.Context) error {
+	var err error
+
+	tm.shutdownOnce.Do(func() {
+		close(tm.stopped)
+
+		err = tm.waitForInFlight(ctx)
+
+		tm.wg.Wait()
+
+		if closeErr := tm.broker.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	})
+
+	return err
+}
+
+// waitForInFlight bloqueia até nenhuma tarefa estar mais em
+// TaskStatusAssigned/TaskStatusRunning, ou até ctx expirar.
+func (tm *TaskManager) waitForInFlight(ctx context.Context) error {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if !tm.hasInFlightTasks() {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("tempo esgotado aguardando tarefas em andamento: %v", ctx.Err())
+		}
+	}
+}
+
+// hasInFlightTasks informa se alguma tarefa está atribuída ou em execução.
+func (tm *TaskManager) hasInFlightTasks() bool {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	for _, task := range tm.tasks {
+		if task.Status == TaskStatusAssigned || task.Status == TaskStatusRunning {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package agents
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func newTestTaskManager(t *testing.T) *TaskManager {
+	t.Helper()
+
+	tm, err := NewTaskManager(NewInMemoryBroker(), prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewTaskManager retornou erro: %v", err)
+	}
+	return tm
+}
+
+// TestAddTaskRejectsEmptyID confirma que task.ID é obrigatório: AddTask não
+// pode enfileirar uma tarefa sem identidade estável para reenvio idempotente.
+func TestAddTaskRejectsEmptyID(t *testing.T) {
+	tm := newTestTaskManager(t)
+
+	if _, err := tm.AddTask(NewTask("quiz", PriorityNormal)); err == nil {
+		t.Fatal("AddTask com task.ID vazio deveria retornar erro")
+	}
+}
+
+// TestAddTaskIdempotentID confirma o contrato documentado em
+// ErrTaskIDConflict: reenviar uma Task com o mesmo ID (ex.: após uma
+// reconexão do RabbitMQ) é rejeitado em vez de duplicar o trabalho.
+func TestAddTaskIdempotentID(t *testing.T) {
+	tm := newTestTaskManager(t)
+
+	first, err := tm.AddTask(NewTask("quiz", PriorityNormal, TaskID("task-1")))
+	if err != nil {
+		t.Fatalf("primeiro AddTask retornou erro: %v", err)
+	}
+	if first.ID != "task-1" {
+		t.Fatalf("task.ID = %q, esperado %q", first.ID, "task-1")
+	}
+
+	if _, err := tm.AddTask(NewTask("quiz", PriorityNormal, TaskID("task-1"))); err != ErrTaskIDConflict {
+		t.Fatalf("segundo AddTask com o mesmo ID retornou %v, esperado ErrTaskIDConflict", err)
+	}
+}
+
+// TestAddTaskRejectsSelfDependency confirma que uma tarefa não pode aparecer
+// em seu próprio DependsOn.
+func TestAddTaskRejectsSelfDependency(t *testing.T) {
+	tm := newTestTaskManager(t)
+
+	task := NewTask("quiz", PriorityNormal, TaskID("task-1"))
+	task.DependsOn = []TaskDependency{{TaskID: "task-1", Strategy: DependSuccess}}
+
+	if _, err := tm.AddTask(task); err == nil {
+		t.Fatal("AddTask com dependência em si mesma deveria retornar erro")
+	}
+}
@@ -0,0 +1,42 @@
+package agents
+
+import "testing"
+
+// TestIsLegalTransitionWhitelist cobre a whitelist em taskTransitions e as
+// duas exceções que isLegalTransition trata à parte: Failed/Rejected são
+// sempre alcançáveis, e Shutdown só a partir de Running com DesiredState já
+// marcado como Shutdown.
+func TestIsLegalTransitionWhitelist(t *testing.T) {
+	tests := []struct {
+		name    string
+		current TaskStatus
+		next    TaskStatus
+		desired TaskStatus
+		want    bool
+	}{
+		{"pending para allocated é permitida", TaskStatusPending, TaskStatusAllocated, "", true},
+		{"pending para assigned pula allocated, é permitida", TaskStatusPending, TaskStatusAssigned, "", true},
+		{"pending para running não está na whitelist", TaskStatusPending, TaskStatusRunning, "", false},
+		{"allocated para assigned é permitida", TaskStatusAllocated, TaskStatusAssigned, "", true},
+		{"assigned para running é permitida", TaskStatusAssigned, TaskStatusRunning, "", true},
+		{"running para complete é permitida", TaskStatusRunning, TaskStatusComplete, "", true},
+		{"complete para retained é permitida", TaskStatusComplete, TaskStatusRetained, "", true},
+		{"complete para running não é permitida", TaskStatusComplete, TaskStatusRunning, "", false},
+		{"qualquer estado para failed é sempre permitida", TaskStatusRunning, TaskStatusFailed, "", true},
+		{"qualquer estado para rejected é sempre permitida", TaskStatusPreparing, TaskStatusRejected, "", true},
+		{"running para shutdown com DesiredState=shutdown é permitida", TaskStatusRunning, TaskStatusShutdown, TaskStatusShutdown, true},
+		{"running para shutdown sem DesiredState marcado não é permitida", TaskStatusRunning, TaskStatusShutdown, "", false},
+		{"assigned para shutdown não é permitida mesmo com DesiredState marcado", TaskStatusAssigned, TaskStatusShutdown, TaskStatusShutdown, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{DesiredState: tt.desired}
+			got := task.isLegalTransition(tt.current, tt.next)
+			if got != tt.want {
+				t.Errorf("isLegalTransition(%s, %s) com DesiredState=%q = %v, esperado %v",
+					tt.current, tt.next, tt.desired, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Trainable é implementado por qualquer agente que possa ser registrado em um
+// AgentTrainer. BaseAgent satisfaz Trainable diretamente; CognitiveAgent a
+// satisfaz através do *BaseAgent embutido.
+type Trainable interface {
+	Train(ctx context.Context, config TrainingConfig) (*TrainingMetrics, error)
+	Validate(ctx context.Context) error
+}
+
+// AgentTrainer coordena o treinamento de um conjunto de agentes com a mesma
+// TrainingConfig, treinando-os concorrentemente e agregando suas métricas.
+type AgentTrainer struct {
+	config TrainingConfig
+
+	mu      sync.Mutex
+	agents  []Trainable
+	metrics map[Trainable]*TrainingMetrics
+}
+
+// NewAgentTrainer cria um AgentTrainer com a configuração de treinamento
+// informada.
+func NewAgentTrainer(config TrainingConfig) *AgentTrainer {
+	return &AgentTrainer{
+		config:  config,
+		metrics: make(map[Trainable]*TrainingMetrics),
+	}
+}
+
+// AddAgent registra um agente a ser treinado pela próxima chamada a Train.
+func (t *AgentTrainer) AddAgent(agent Trainable) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.agents = append(t.agents, agent)
+}
+
+// Train valida e treina todos os agentes registrados concorrentemente,
+// coletando suas TrainingMetrics. Retorna o primeiro erro encontrado, se
+// houver, mas sempre espera todos os agentes terminarem antes de retornar.
+func (t *AgentTrainer) Train(ctx context.Context) error {
+	t.mu.Lock()
+	agentsSnapshot := make([]Trainable, len(t.agents))
+	copy(agentsSnapshot, t.agents)
+	t.mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(agentsSnapshot))
+
+	for i, agent := range agentsSnapshot {
+		wg.Add(1)
+		go func(i int, agent Trainable) {
+			defer wg.Done()
+
+			if err := agent.Validate(ctx); err != nil {
+				errs[i] = fmt.Errorf("erro ao validar agente antes do treinamento: %v", err)
+				return
+			}
+
+			metrics, err := agent.Train(ctx, t.config)
+			if err != nil {
+				errs[i] = fmt.Errorf("erro ao treinar agente: %v", err)
+			}
+
+			t.mu.Lock()
+			t.metrics[agent] = metrics
+			t.mu.Unlock()
+		}(i, agent)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAllMetrics retorna as métricas coletadas na última chamada a Train,
+// indexadas pelo agente correspondente.
+func (t *AgentTrainer) GetAllMetrics() map[Trainable]*TrainingMetrics {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make(map[Trainable]*TrainingMetrics, len(t.metrics))
+	for k, v := range t.metrics {
+		result[k] = v
+	}
+	return result
+}
@@ -1,6 +1,12 @@
 package agents
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
 
 // Task representa uma tarefa a ser executada por um agente
 type Task struct {
@@ -14,16 +20,255 @@ type Task struct {
 	CreatedAt      time.Time    `json:"created_at"`
 	AssignedTo     string       `json:"assigned_to,omitempty"`
 	CompletedAt    *time.Time   `json:"completed_at,omitempty"`
+
+	// Description é o prompt em texto livre enviado ao LLM por
+	// HiveMind.Execute — distinto de ExpectedOutput/FormatOutput, que
+	// descrevem o formato da resposta, não a tarefa em si.
+	Description string `json:"description,omitempty"`
+
+	// Agent é o Agent que HiveMind.Execute usa para montar a requisição ao
+	// provedor de LLM (Model, Role, Goal, Backstory, ...). Preenchido pelo
+	// chamador de AssignTasks ou, se vazio, pelo primeiro Agent de
+	// HiveMind.Agents. Não tem relação com AssignedTo, que identifica o
+	// CognitiveAgent dono da tarefa no TaskManager.
+	Agent *Agent `json:"-"`
+
+	// Retention é por quanto tempo, após a conclusão, o Result permanece
+	// disponível via TaskManager.GetTaskResult antes de ser evictado — no
+	// espírito do retention de resultados do asynq. Zero desabilita a
+	// eviction: a tarefa fica em TaskStatusComplete e seu Result continua
+	// disponível via GetTaskResult sem prazo.
+	Retention time.Duration `json:"retention,omitempty"`
+
+	// Result acumula os bytes do resultado da tarefa, tipicamente escritos
+	// aos poucos através de ResultWriter em vez de atribuídos de uma vez só.
+	Result []byte `json:"result,omitempty"`
+
+	// RetryCount conta quantas vezes esta tarefa já foi atribuída e falhou.
+	// scoreCandidate usa isso como penalidade para que tarefas que falham
+	// repetidamente não monopolizem tentativas às custas de tarefas novas.
+	RetryCount int `json:"retry_count,omitempty"`
+
+	// DesiredState espelha Status, mas é escrito pelo orquestrador para pedir
+	// uma transição (ex.: TaskStatusShutdown) independente do que o agente
+	// observa e reporta via Transition. Status é o que aconteceu; DesiredState
+	// é o que deveria acontecer.
+	DesiredState TaskStatus `json:"desired_state,omitempty"`
+
+	// Error descreve por que esta tarefa chegou a TaskStatusFailed — tanto
+	// por uma falha própria quanto, nesse caso preenchido por
+	// TaskManager.failDependents, porque uma dependência obrigatória em
+	// DependsOn terminou sem satisfazer sua DependStrategy. Ver LastError
+	// para o equivalente em string, mantido por compatibilidade.
+	Error *TaskError `json:"error,omitempty"`
+
+	// DependsOn declara as tarefas das quais esta depende antes de se tornar
+	// elegível em GetNextTask — ver Ready e DependStrategy.
+	DependsOn []TaskDependency `json:"depends_on,omitempty"`
+
+	// Stages reporta o progresso de uma tarefa de múltiplas fases (ex.:
+	// pesquisar → rascunhar → verificar → formatar) incrementalmente, em vez
+	// de só um Status terminal — atualizado via AgentReporter.ReportStage.
+	Stages []TaskStage `json:"stages,omitempty"`
+
+	resultLock sync.Mutex
+	stateLock  sync.Mutex
+	events     chan TaskEvent
+}
+
+// TaskStageStatus representa o estado de um TaskStage individual.
+type TaskStageStatus string
+
+const (
+	StageStatusPending  TaskStageStatus = "pending"
+	StageStatusRunning  TaskStageStatus = "running"
+	StageStatusComplete TaskStageStatus = "complete"
+	StageStatusFailed   TaskStageStatus = "failed"
+)
+
+// TaskStage é uma etapa nomeada dentro do ciclo de vida de uma Task de
+// múltiplas fases. SortBy ordena as etapas para exibição (ex.: num Gantt),
+// independente da ordem em que ReportStage as atualiza. PlanCompletedAt é
+// preenchido antecipadamente por quem monta a Task; RealCompletedAt é
+// preenchido por AgentReporter.ReportStage quando Status chega a
+// StageStatusComplete.
+type TaskStage struct {
+	Name              string          `json:"name"`
+	SortBy            int             `json:"sort_by"`
+	Status            TaskStageStatus `json:"status"`
+	PlanCompletedAt   *time.Time      `json:"plan_completed_at,omitempty"`
+	RealCompletedAt   *time.Time      `json:"real_completed_at,omitempty"`
+	StatusDescription string          `json:"status_description,omitempty"`
+}
+
+// LastError devolve a mensagem de t.Error, ou "" se a tarefa não tiver
+// falhado — mantido como getter derivado para compatibilidade com código que
+// ainda trata o erro de uma tarefa como string simples.
+func (t *Task) LastError() string {
+	if t.Error == nil {
+		return ""
+	}
+	return t.Error.Message
+}
+
+// Progress resume quantas de t.Stages já chegaram a StageStatusComplete. Uma
+// Task sem Stages reporta 0/0.
+func (t *Task) Progress() (completed, total int) {
+	total = len(t.Stages)
+	for _, stage := range t.Stages {
+		if stage.Status == StageStatusComplete {
+			completed++
+		}
+	}
+	return completed, total
+}
+
+// taskEventBuffer é o tamanho do buffer do canal de TaskEvent de uma Task —
+// suficiente para um assinante (tipicamente o TaskManager) drenar sem que
+// Transition bloqueie sob uso normal.
+const taskEventBuffer = 16
+
+// TaskEvent registra uma transição de estado de uma Task, publicada por
+// Transition no canal retornado por Task.Events para que assinantes montem
+// logs de auditoria e dashboards do ciclo de vida da tarefa.
+type TaskEvent struct {
+	TaskID string     `json:"task_id"`
+	From   TaskStatus `json:"from"`
+	To     TaskStatus `json:"to"`
+	At     time.Time  `json:"at"`
+}
+
+// Events retorna o canal de TaskEvent desta tarefa, criando-o sob demanda na
+// primeira chamada. Chamadas subsequentes reutilizam o mesmo canal, então só
+// o primeiro assinante efetivamente recebe eventos.
+func (t *Task) Events() <-chan TaskEvent {
+	t.stateLock.Lock()
+	defer t.stateLock.Unlock()
+	if t.events == nil {
+		t.events = make(chan TaskEvent, taskEventBuffer)
+	}
+	return t.events
+}
+
+// ErrIllegalTransition é retornado por Transition quando next não é
+// alcançável a partir do Status atual da tarefa, segundo taskTransitions.
+var ErrIllegalTransition = errors.New("transição de estado ilegal")
+
+// taskTransitions é a whitelist de transições de estado legais, inspirada no
+// ciclo de vida de tasks do Docker Swarm: Pending passa por Allocated,
+// Assigned, Accepted, Preparing, Ready e Starting antes de chegar a Running e,
+// por fim, Complete. TaskStatusFailed e TaskStatusRejected não aparecem aqui
+// porque são sempre alcançáveis, de qualquer estado — ver isLegalTransition.
+var taskTransitions = map[TaskStatus][]TaskStatus{
+	TaskStatusPending:   {TaskStatusAllocated, TaskStatusAssigned},
+	TaskStatusAllocated: {TaskStatusAssigned},
+	TaskStatusAssigned:  {TaskStatusAccepted, TaskStatusRunning},
+	TaskStatusAccepted:  {TaskStatusPreparing},
+	TaskStatusPreparing: {TaskStatusReady},
+	TaskStatusReady:     {TaskStatusStarting},
+	TaskStatusStarting:  {TaskStatusRunning},
+	TaskStatusRunning:   {TaskStatusComplete, TaskStatusShutdown},
+	TaskStatusComplete:  {TaskStatusRetained},
+}
+
+// Transition move a tarefa de seu Status atual para next, validando contra
+// taskTransitions, e publica um TaskEvent no canal de Events em caso de
+// sucesso. TaskStatusShutdown só é aceito a partir de TaskStatusRunning e
+// apenas quando DesiredState já foi marcado como TaskStatusShutdown pelo
+// orquestrador — Transition nunca muda DesiredState por conta própria.
+func (t *Task) Transition(next TaskStatus) error {
+	t.stateLock.Lock()
+	defer t.stateLock.Unlock()
+
+	current := t.Status
+	if !t.isLegalTransition(current, next) {
+		return fmt.Errorf("%w: %s -> %s", ErrIllegalTransition, current, next)
+	}
+
+	t.Status = next
+
+	if t.events != nil {
+		event := TaskEvent{TaskID: t.ID, From: current, To: next, At: time.Now()}
+		select {
+		case t.events <- event:
+		default:
+			log.Printf("⚠️ canal de eventos da tarefa %s cheio, evento %s->%s descartado", t.ID, current, next)
+		}
+	}
+
+	return nil
 }
 
-// Agent representa um agente base com características comuns
-type Agent struct {
-	Name            string `json:"name"`
-	Role            string `json:"role"`
-	Goal            string `json:"goal"`
-	AllowDelegation bool   `json:"allow_delegation"`
-	Model           string `json:"model"`
-	Backstory       string `json:"backstory"`
+// isLegalTransition implementa a whitelist descrita em taskTransitions, mais
+// as duas exceções que não cabem num mapa simples: Failed/Rejected a partir
+// de qualquer estado, e Shutdown condicionado a DesiredState.
+func (t *Task) isLegalTransition(current, next TaskStatus) bool {
+	if next == TaskStatusFailed || next == TaskStatusRejected {
+		return true
+	}
+	if next == TaskStatusShutdown {
+		return current == TaskStatusRunning && t.DesiredState == TaskStatusShutdown
+	}
+	for _, allowed := range taskTransitions[current] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ResultWriter permite que um agente grave o resultado de uma tarefa de forma
+// incremental, sem precisar saber como ou onde esse resultado é armazenado.
+type ResultWriter interface {
+	Write(p []byte) (n int, err error)
+}
+
+// taskResultWriter implementa ResultWriter acrescentando bytes ao Result de
+// uma Task, protegido por seu próprio mutex para aceitar escritas de
+// goroutines concorrentes sem depender do lock do TaskManager.
+type taskResultWriter struct {
+	task *Task
+}
+
+// Write acrescenta p ao resultado acumulado da tarefa.
+func (w *taskResultWriter) Write(p []byte) (int, error) {
+	w.task.resultLock.Lock()
+	defer w.task.resultLock.Unlock()
+	w.task.Result = append(w.task.Result, p...)
+	return len(p), nil
+}
+
+// ResultWriter retorna um ResultWriter para esta tarefa, usado por agentes
+// que produzem o resultado em partes (streaming) em vez de de uma vez só.
+func (t *Task) ResultWriter() ResultWriter {
+	return &taskResultWriter{task: t}
+}
+
+// TaskOption customiza uma Task construída via NewTask.
+type TaskOption func(*Task)
+
+// TaskID define o ID da tarefa. TaskManager.AddTask usa esse ID para garantir
+// idempotência: republicar a mesma Task (mesmo ID) após uma reconexão do
+// RabbitMQ retorna ErrTaskIDConflict em vez de duplicar o trabalho.
+func TaskID(id string) TaskOption {
+	return func(t *Task) { t.ID = id }
+}
+
+// NewTask cria uma Task do tipo e prioridade informados, já em
+// TaskStatusPending, aplicando as TaskOption fornecidas (tipicamente TaskID)
+// por cima dos padrões.
+func NewTask(taskType string, priority TaskPriority, opts ...TaskOption) *Task {
+	task := &Task{
+		Type:     taskType,
+		Priority: priority,
+		Status:   TaskStatusPending,
+	}
+
+	for _, opt := range opts {
+		opt(task)
+	}
+
+	return task
 }
 
 // TaskPriority define a prioridade da tarefa
@@ -44,8 +289,105 @@ const (
 	TaskStatusRunning  TaskStatus = "running"
 	TaskStatusComplete TaskStatus = "complete"
 	TaskStatusFailed   TaskStatus = "failed"
+
+	// TaskStatusRetained marca uma tarefa concluída cujo Result ainda está
+	// disponível via TaskManager.GetTaskResult, aguardando a eviction
+	// agendada para quando Retention elapsed.
+	TaskStatusRetained TaskStatus = "retained"
+
+	// Estados intermediários do ciclo de vida, no espírito do Docker Swarm:
+	// uma tarefa é Allocated a um agente antes de ser formalmente Assigned,
+	// que por sua vez a Accepted antes de Preparing seu ambiente de execução,
+	// ficando Ready e então Starting até finalmente Running. Ver
+	// taskTransitions para a ordem exata.
+	TaskStatusAllocated TaskStatus = "allocated"
+	TaskStatusAccepted  TaskStatus = "accepted"
+	TaskStatusPreparing TaskStatus = "preparing"
+	TaskStatusReady     TaskStatus = "ready"
+	TaskStatusStarting  TaskStatus = "starting"
+
+	// TaskStatusRejected marca uma tarefa que um agente recusou antes de
+	// executá-la (ex.: falhou em Accepted ou Preparing), distinto de
+	// TaskStatusFailed, que marca uma falha durante ou após a execução.
+	TaskStatusRejected TaskStatus = "rejected"
+
+	// TaskStatusShutdown marca uma tarefa Running encerrada a pedido do
+	// orquestrador (DesiredState == TaskStatusShutdown), distinto de uma
+	// falha espontânea do agente.
+	TaskStatusShutdown TaskStatus = "shutdown"
+)
+
+// DependStrategy define quando uma TaskDependency é considerada satisfeita.
+type DependStrategy string
+
+const (
+	// DependSuccess exige que o pai tenha chegado a TaskStatusComplete (ou
+	// TaskStatusRetained, seu sucessor imediato quando há Retention). É o
+	// padrão quando Strategy é deixado em branco.
+	DependSuccess DependStrategy = "success"
+
+	// DependAtLeastOnce é satisfeita assim que o pai chega a
+	// TaskStatusComplete pela primeira vez — diferente de DependSuccess
+	// apenas para deixar explícito, num pipeline com retries, que um sucesso
+	// passageiro já basta, mesmo que o pai venha a falhar depois (o que hoje
+	// não acontece: uma Task concluída não volta a rodar).
+	DependAtLeastOnce DependStrategy = "at_least_once"
+
+	// DependSameAgent exige que o pai tenha concluído com sucesso e que a
+	// dependente seja atribuída ao mesmo agente que o processou, para
+	// reaproveitar estado ou cache local mantido por esse agente.
+	// Task.Ready não consegue avaliar a parte "mesmo agente" sozinho, pois
+	// não conhece o agente candidato — GetNextTask confere isso à parte.
+	DependSameAgent DependStrategy = "same_agent"
+
+	// DependAny é satisfeita assim que o pai chega a qualquer estado
+	// terminal — Complete ou Failed/Rejected/Shutdown — útil para etapas de
+	// limpeza que devem rodar independente do resultado do pai.
+	DependAny DependStrategy = "any"
 )
 
+// TaskDependency declara que uma Task depende de outra, identificada por
+// TaskID, segundo Strategy.
+type TaskDependency struct {
+	TaskID   string         `json:"task_id"`
+	Strategy DependStrategy `json:"strategy"`
+}
+
+// Ready indica se todas as dependências de t já estão satisfeitas, segundo o
+// TaskStatus mais recente de cada uma em completed (tipicamente um snapshot
+// de TaskManager.tasks). Dependências cujo pai ainda não aparece em completed
+// são tratadas como não satisfeitas. DependSameAgent é avaliado aqui como
+// DependSuccess; a parte "mesmo agente" só o dispatcher, que conhece o
+// agente candidato, pode confirmar.
+func (t *Task) Ready(completed map[string]TaskStatus) bool {
+	for _, dep := range t.DependsOn {
+		status, ok := completed[dep.TaskID]
+		if !ok {
+			return false
+		}
+		if dep.Strategy == DependAny {
+			if !isTerminal(status) {
+				return false
+			}
+			continue
+		}
+		if status != TaskStatusComplete && status != TaskStatusRetained {
+			return false
+		}
+	}
+	return true
+}
+
+// LastError devolve a mensagem de h.Error, ou "" se o agente não tiver um
+// erro registrado — mantido como getter derivado para compatibilidade com
+// código que ainda trata o erro de um agente como string simples.
+func (h *AgentHealth) LastError() string {
+	if h.Error == nil {
+		return ""
+	}
+	return h.Error.Message
+}
+
 // AgentHealth representa o estado de saúde de um agente
 type AgentHealth struct {
 	AgentName      string    `json:"agent_name"`
@@ -54,6 +396,19 @@ type AgentHealth struct {
 	CurrentTaskID  string    `json:"current_task_id,omitempty"`
 	ProcessingTime float64   `json:"processing_time"` // tempo médio de processamento em segundos
 	SuccessRate    float64   `json:"success_rate"`    // taxa de sucesso (0-1)
-	LastError      string    `json:"last_error,omitempty"`
-	LastErrorTime  time.Time `json:"last_error_time,omitempty"`
+
+	// Error descreve a última falha observada neste agente. Code
+	// alimenta o agrupamento por assinatura de erro do
+	// AnomalyDetector (ver AnomalyRepeatedError) em vez de comparar a
+	// mensagem como texto solto.
+	Error         *TaskError `json:"error,omitempty"`
+	LastErrorTime time.Time  `json:"last_error_time,omitempty"`
+
+	// ProbeState é o agregado das HealthCheck do agente (o pior estado entre
+	// todas as sondas registradas em seu HealthCheckRegistry), usado por
+	// TaskManager.GetNextTask para recusar atribuir tarefas a agentes
+	// Unhealthy. Probes traz o detalhe por sonda, consultado via
+	// TaskManager.GetAgentProbeStatus.
+	ProbeState ProbeState            `json:"probe_state,omitempty"`
+	Probes     map[string]ProbeState `json:"probes,omitempty"`
 }
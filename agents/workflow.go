@@ -0,0 +1,92 @@
+package agents
+
+import "fmt"
+
+// Workflow agrupa um conjunto de Task relacionadas por DependsOn num DAG,
+// submetidas ao TaskManager como uma unidade só — ex.: gerar-quiz → corrigir
+// → resumir, onde cada etapa declara a anterior em DependsOn.
+type Workflow struct {
+	ID    string
+	Tasks []*Task
+}
+
+// NewWorkflow monta um Workflow a partir de tasks, rejeitando ciclos de
+// dependência internos ao próprio workflow antes que qualquer Task chegue a
+// TaskManager.AddTask. Dependências que apontam para fora de tasks (ex.: uma
+// etapa de um workflow anterior já concluído) não são validadas aqui — quem
+// garante que existiram é o próprio TaskManager, via Task.Ready.
+func NewWorkflow(id string, tasks []*Task) (*Workflow, error) {
+	byID := make(map[string]*Task, len(tasks))
+	for _, t := range tasks {
+		if t.ID == "" {
+			return nil, fmt.Errorf("workflow %s: task sem ID não pode declarar dependências", id)
+		}
+		if _, dup := byID[t.ID]; dup {
+			return nil, fmt.Errorf("workflow %s: task %s duplicada", id, t.ID)
+		}
+		byID[t.ID] = t
+	}
+
+	if cycle := findDependencyCycle(tasks, byID); cycle != "" {
+		return nil, fmt.Errorf("workflow %s: ciclo de dependências detectado envolvendo a task %s", id, cycle)
+	}
+
+	return &Workflow{ID: id, Tasks: tasks}, nil
+}
+
+// findDependencyCycle percorre em profundidade o grafo de DependsOn restrito
+// a byID, devolvendo o ID da primeira task revisitada no mesmo caminho
+// (indicando um ciclo), ou "" se o subgrafo for um DAG válido.
+func findDependencyCycle(tasks []*Task, byID map[string]*Task) string {
+	const (
+		unvisited = 0
+		visiting  = 1
+		visited   = 2
+	)
+	state := make(map[string]int, len(tasks))
+
+	var visit func(id string) string
+	visit = func(id string) string {
+		switch state[id] {
+		case visited:
+			return ""
+		case visiting:
+			return id
+		}
+
+		state[id] = visiting
+		if task, ok := byID[id]; ok {
+			for _, dep := range task.DependsOn {
+				if _, internal := byID[dep.TaskID]; !internal {
+					continue
+				}
+				if cyc := visit(dep.TaskID); cyc != "" {
+					return cyc
+				}
+			}
+		}
+		state[id] = visited
+
+		return ""
+	}
+
+	for _, t := range tasks {
+		if cyc := visit(t.ID); cyc != "" {
+			return cyc
+		}
+	}
+	return ""
+}
+
+// Submit adiciona todas as Task do Workflow a tm de uma só vez. Tasks sem
+// DependsOn ficam imediatamente elegíveis em GetNextTask; as demais
+// permanecem em TaskStatusPending até Task.Ready reconhecer suas
+// dependências como satisfeitas.
+func (w *Workflow) Submit(tm *TaskManager) error {
+	for _, t := range w.Tasks {
+		if _, err := tm.AddTask(t); err != nil {
+			return fmt.Errorf("workflow %s: erro ao submeter task %s: %v", w.ID, t.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package agents
+
+import "testing"
+
+// TestNewWorkflowAcceptsValidDAG confirma que um grafo de dependências sem
+// ciclos é aceito.
+func TestNewWorkflowAcceptsValidDAG(t *testing.T) {
+	generate := NewTask("quiz", PriorityNormal, TaskID("generate"))
+	grade := NewTask("grade", PriorityNormal, TaskID("grade"))
+	grade.DependsOn = []TaskDependency{{TaskID: "generate", Strategy: DependSuccess}}
+	summarize := NewTask("summarize", PriorityNormal, TaskID("summarize"))
+	summarize.DependsOn = []TaskDependency{{TaskID: "grade", Strategy: DependSuccess}}
+
+	wf, err := NewWorkflow("quiz-flow", []*Task{generate, grade, summarize})
+	if err != nil {
+		t.Fatalf("NewWorkflow retornou erro para um DAG válido: %v", err)
+	}
+	if len(wf.Tasks) != 3 {
+		t.Fatalf("len(wf.Tasks) = %d, esperado 3", len(wf.Tasks))
+	}
+}
+
+// TestNewWorkflowRejectsDirectCycle confirma que duas tasks que dependem uma
+// da outra são rejeitadas.
+func TestNewWorkflowRejectsDirectCycle(t *testing.T) {
+	a := NewTask("a", PriorityNormal, TaskID("a"))
+	a.DependsOn = []TaskDependency{{TaskID: "b", Strategy: DependSuccess}}
+	b := NewTask("b", PriorityNormal, TaskID("b"))
+	b.DependsOn = []TaskDependency{{TaskID: "a", Strategy: DependSuccess}}
+
+	if _, err := NewWorkflow("cyclic", []*Task{a, b}); err == nil {
+		t.Fatal("NewWorkflow deveria rejeitar um ciclo direto entre duas tasks")
+	}
+}
+
+// TestNewWorkflowRejectsIndirectCycle confirma que findDependencyCycle
+// também detecta ciclos que passam por mais de duas tasks.
+func TestNewWorkflowRejectsIndirectCycle(t *testing.T) {
+	a := NewTask("a", PriorityNormal, TaskID("a"))
+	a.DependsOn = []TaskDependency{{TaskID: "c", Strategy: DependSuccess}}
+	b := NewTask("b", PriorityNormal, TaskID("b"))
+	b.DependsOn = []TaskDependency{{TaskID: "a", Strategy: DependSuccess}}
+	c := NewTask("c", PriorityNormal, TaskID("c"))
+	c.DependsOn = []TaskDependency{{TaskID: "b", Strategy: DependSuccess}}
+
+	if _, err := NewWorkflow("cyclic-indirect", []*Task{a, b, c}); err == nil {
+		t.Fatal("NewWorkflow deveria rejeitar um ciclo indireto a -> c -> b -> a")
+	}
+}
+
+// TestNewWorkflowIgnoresExternalDependency confirma que uma dependência que
+// aponta para fora do workflow (ex.: uma etapa de um workflow anterior já
+// concluído) não é tratada como parte do grafo de ciclo.
+func TestNewWorkflowIgnoresExternalDependency(t *testing.T) {
+	task := NewTask("grade", PriorityNormal, TaskID("grade"))
+	task.DependsOn = []TaskDependency{{TaskID: "generate-from-previous-workflow", Strategy: DependSuccess}}
+
+	if _, err := NewWorkflow("quiz-flow-2", []*Task{task}); err != nil {
+		t.Fatalf("NewWorkflow retornou erro para uma dependência externa: %v", err)
+	}
+}
+
+// TestNewWorkflowRejectsDuplicateTaskID confirma que duas tasks com o mesmo
+// ID no mesmo workflow são rejeitadas antes de qualquer checagem de ciclo.
+func TestNewWorkflowRejectsDuplicateTaskID(t *testing.T) {
+	a := NewTask("a", PriorityNormal, TaskID("dup"))
+	b := NewTask("b", PriorityNormal, TaskID("dup"))
+
+	if _, err := NewWorkflow("dup-flow", []*Task{a, b}); err == nil {
+		t.Fatal("NewWorkflow deveria rejeitar duas tasks com o mesmo ID")
+	}
+}